@@ -0,0 +1,60 @@
+package api
+
+import (
+	"context"
+	"time"
+)
+
+// How often a budget starts over
+const (
+	BUDGET_MONTHLY = "monthly"
+	// Weeks start on Monday
+	BUDGET_WEEKLY = "weekly"
+)
+
+var BUDGET_PERIODS = []string{BUDGET_MONTHLY, BUDGET_WEEKLY}
+
+type SavableBudget struct {
+	CategoryID string `json:"categoryId"`
+	Period     string `json:"period"`
+	// Most that should be spent in a period, as a positive number
+	Limit float64 `json:"limit"`
+}
+
+// At most one per category
+type Budget struct {
+	ID string `json:"id"`
+
+	SavableBudget
+}
+
+// Start of the period t is in
+func (b *SavableBudget) PeriodStart(t time.Time) time.Time {
+	if b.Period == BUDGET_WEEKLY {
+		d := (int(t.Weekday()) + 6) % 7
+		return time.Date(t.Year(), t.Month(), t.Day()-d, 0, 0, 0, 0, t.Location())
+	}
+
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+func (c *APIClient) BudgetsFetch(ctx context.Context) ([]*Budget, error) {
+	return deArray(easyFetch[[]*Budget](ctx, c, `GET`, `/budgets`, nil))
+}
+
+func (c *APIClient) BudgetsCreate(ctx context.Context, s *SavableBudget) (string, error) {
+	resp, err := easyFetch[RespCreated](ctx, c, `POST`, `/budgets`, s)
+	if err != nil {
+		return "", err
+	}
+
+	return resp.ID, nil
+}
+
+func (c *APIClient) BudgetsUpdate(ctx context.Context, id string, s *SavableBudget) error {
+	return easyNilFetch(ctx, c, `PUT`, `/budgets/`+id, s)
+}
+
+func (c *APIClient) BudgetsDelete(ctx context.Context, id string) error {
+	return easyNilFetch(ctx, c, `DELETE`, `/budgets/`+id, nil)
+}
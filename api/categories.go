@@ -1,5 +1,7 @@
 package api
 
+import "context"
+
 type SavableCategory struct {
 	Color string `json:"color"`
 	Icon  string `json:"icon"`
@@ -12,12 +14,12 @@ type Category struct {
 	SavableCategory
 }
 
-func (c *APIClient) CategoriesFetch() ([]*Category, error) {
-	return deArray(easyFetch[[]*Category](c, `GET`, `/categories`, nil))
+func (c *APIClient) CategoriesFetch(ctx context.Context) ([]*Category, error) {
+	return deArray(easyFetch[[]*Category](ctx, c, `GET`, `/categories`, nil))
 }
 
-func (c *APIClient) CategoriesCreate(s *SavableCategory) (string, error) {
-	resp, err := easyFetch[RespCreated](c, `POST`, `/categories`, s)
+func (c *APIClient) CategoriesCreate(ctx context.Context, s *SavableCategory) (string, error) {
+	resp, err := easyFetch[RespCreated](ctx, c, `POST`, `/categories`, s)
 	if err != nil {
 		return "", err
 	}
@@ -25,10 +27,10 @@ func (c *APIClient) CategoriesCreate(s *SavableCategory) (string, error) {
 	return resp.ID, nil
 }
 
-func (c *APIClient) CategoriesUpdate(id string, s *SavableCategory) error {
-	return easyNilFetch(c, `PUT`, `/categories/` + id, s)
+func (c *APIClient) CategoriesUpdate(ctx context.Context, id string, s *SavableCategory) error {
+	return easyNilFetch(ctx, c, `PUT`, `/categories/` + id, s)
 }
 
-func (c *APIClient) CategoriesDelete(id string) error {
-	return easyNilFetch(c, `DELETE`, `/categories/` + id, nil)
+func (c *APIClient) CategoriesDelete(ctx context.Context, id string) error {
+	return easyNilFetch(ctx, c, `DELETE`, `/categories/` + id, nil)
 }
@@ -0,0 +1,118 @@
+package fake
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/bank_data_tui/api"
+)
+
+// Adds a budget straight away, returning its id
+func (s *Server) AddBudget(b api.SavableBudget) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := s.newID()
+	s.budgets = append(s.budgets, &api.Budget{ID: id, SavableBudget: b})
+
+	return id
+}
+
+// A copy of every budget
+func (s *Server) Budgets() []api.Budget {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := make([]api.Budget, len(s.budgets))
+	for i, b := range s.budgets {
+		res[i] = *b
+	}
+
+	return res
+}
+
+func (s *Server) budget(id string) *api.Budget {
+	i := slices.IndexFunc(s.budgets, func(b *api.Budget) bool { return b.ID == id })
+	if i == -1 {
+		return nil
+	}
+
+	return s.budgets[i]
+}
+
+// id is the budget being updated, "" for a new one. Needs s.mu
+func (s *Server) validateBudget(id string, b *api.SavableBudget) validation {
+	v := validation{}
+	if s.category(b.CategoryID) == nil {
+		v.add("category", "no category with that id")
+	} else if slices.ContainsFunc(s.budgets, func(o *api.Budget) bool { return o.ID != id && o.CategoryID == b.CategoryID }) {
+		v.add("category", "already has a budget")
+	}
+	if !slices.Contains(api.BUDGET_PERIODS, b.Period) {
+		v.add("period", "needs to be monthly or weekly")
+	}
+	if b.Limit <= 0 {
+		v.add("limit", "needs to be above 0")
+	}
+
+	return v
+}
+
+func (s *Server) budgetsList(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.Budgets())
+}
+
+func (s *Server) budgetsCreate(w http.ResponseWriter, r *http.Request) {
+	var b api.SavableBudget
+	if !readJSON(w, r, &b) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.validateBudget("", &b).write(w) {
+		return
+	}
+
+	id := s.newID()
+	s.budgets = append(s.budgets, &api.Budget{ID: id, SavableBudget: b})
+
+	writeJSON(w, http.StatusOK, api.RespCreated{ID: id})
+}
+
+func (s *Server) budgetsUpdate(w http.ResponseWriter, r *http.Request) {
+	var b api.SavableBudget
+	if !readJSON(w, r, &b) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	have := s.budget(r.PathValue("id"))
+	if have == nil {
+		writeErr(w, http.StatusNotFound, "no budget with that id")
+		return
+	}
+	if s.validateBudget(have.ID, &b).write(w) {
+		return
+	}
+	have.SavableBudget = b
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) budgetsDelete(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	if s.budget(id) == nil {
+		writeErr(w, http.StatusNotFound, "no budget with that id")
+		return
+	}
+	s.budgets = slices.DeleteFunc(s.budgets, func(b *api.Budget) bool { return b.ID == id })
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,121 @@
+package fake
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/bank_data_tui/api"
+	"github.com/rivo/uniseg"
+)
+
+// Adds a category straight away, returning its id
+func (s *Server) AddCategory(c api.SavableCategory) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := s.newID()
+	s.categories = append(s.categories, &api.Category{ID: id, SavableCategory: c})
+
+	return id
+}
+
+// A copy of every category
+func (s *Server) Categories() []api.Category {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := make([]api.Category, len(s.categories))
+	for i, c := range s.categories {
+		res[i] = *c
+	}
+
+	return res
+}
+
+func (s *Server) category(id string) *api.Category {
+	i := slices.IndexFunc(s.categories, func(c *api.Category) bool { return c.ID == id })
+	if i == -1 {
+		return nil
+	}
+
+	return s.categories[i]
+}
+
+func validateCategory(c *api.SavableCategory) validation {
+	v := validation{}
+	if c.Name == "" {
+		v.add("name", "can't be empty")
+	}
+	if _, err := strconv.ParseUint(c.Color, 16, 64); len(c.Color) != 6 || err != nil {
+		v.add("color", "needs a hex color (no #)")
+	}
+	if uniseg.GraphemeClusterCount(c.Icon) != 1 {
+		v.add("icon", "needs to be a single character")
+	}
+
+	return v
+}
+
+func (s *Server) categoriesList(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.Categories())
+}
+
+func (s *Server) categoriesCreate(w http.ResponseWriter, r *http.Request) {
+	var c api.SavableCategory
+	if !readJSON(w, r, &c) || validateCategory(&c).write(w) {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, api.RespCreated{ID: s.AddCategory(c)})
+}
+
+func (s *Server) categoriesUpdate(w http.ResponseWriter, r *http.Request) {
+	var c api.SavableCategory
+	if !readJSON(w, r, &c) || validateCategory(&c).write(w) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	have := s.category(r.PathValue("id"))
+	if have == nil {
+		writeErr(w, http.StatusNotFound, "no category with that id")
+		return
+	}
+	have.SavableCategory = c
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Whatever pointed at the category loses it, & its budget goes with it
+func (s *Server) categoriesDelete(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	if s.category(id) == nil {
+		writeErr(w, http.StatusNotFound, "no category with that id")
+		return
+	}
+	s.categories = slices.DeleteFunc(s.categories, func(c *api.Category) bool { return c.ID == id })
+	s.budgets = slices.DeleteFunc(s.budgets, func(b *api.Budget) bool { return b.CategoryID == id })
+
+	for _, m := range s.mappings {
+		if m.ResCategoryID == id {
+			m.ResCategoryID = ""
+		}
+	}
+	for _, t := range s.transactions {
+		if t.override.CategoryID != nil && *t.override.CategoryID == id {
+			t.override.CategoryID = nil
+			t.setOverridden()
+		}
+		if t.ResolvedCategoryID != nil && *t.ResolvedCategoryID == id {
+			t.ResolvedCategoryID = nil
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
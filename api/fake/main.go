@@ -0,0 +1,220 @@
+// An in-memory stand-in for the bank data server, for tests (through httptest) & working offline. It answers the
+// same routes with the same error shapes as the real one, but keeps nothing between runs
+package fake
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bank_data_tui/api"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const DEFAULT_TOKEN_LIFE = time.Hour
+
+// Page size when the client doesn't ask for one, & the most it can ask for
+const (
+	DEFAULT_PAGE_SIZE = 50
+	MAX_PAGE_SIZE     = 500
+)
+
+// A transaction as the server keeps it. The resolved values in Transaction are what was last applied, which
+// can be behind the mappings when they were changed with no_retroactive
+type transaction struct {
+	api.Transaction
+	override api.TransactionOverride
+}
+
+type Server struct {
+	// How long the tokens it hands out last. Changing it only affects new ones
+	TokenLife time.Duration
+
+	mu  sync.Mutex
+	mux *http.ServeMux
+	key []byte
+	// IDs of the tokens that are still good, see RevokeTokens
+	tokens map[string]bool
+	lastID int
+
+	users        map[string]string
+	categories   []*api.Category
+	budgets      []*api.Budget
+	mappings     []*api.Mapping
+	transactions []*transaction
+}
+
+var _ http.Handler = &Server{} // compile check
+
+// An empty server without any users. Use it as a handler, ie. httptest.NewServer(fake.New())
+func New() *Server {
+	s := &Server{
+		TokenLife: DEFAULT_TOKEN_LIFE,
+		mux:       http.NewServeMux(),
+		key:       make([]byte, 32),
+		users:     map[string]string{},
+		tokens:    map[string]bool{},
+	}
+	rand.Read(s.key)
+
+	s.mux.HandleFunc("POST /login", s.login)
+
+	s.mux.HandleFunc("GET /transactions", s.auth(s.transactionsList))
+	s.mux.HandleFunc("PUT /transactions/{id}", s.auth(s.transactionsUpdate))
+
+	s.mux.HandleFunc("GET /categories", s.auth(s.categoriesList))
+	s.mux.HandleFunc("POST /categories", s.auth(s.categoriesCreate))
+	s.mux.HandleFunc("PUT /categories/{id}", s.auth(s.categoriesUpdate))
+	s.mux.HandleFunc("DELETE /categories/{id}", s.auth(s.categoriesDelete))
+
+	s.mux.HandleFunc("GET /budgets", s.auth(s.budgetsList))
+	s.mux.HandleFunc("POST /budgets", s.auth(s.budgetsCreate))
+	s.mux.HandleFunc("PUT /budgets/{id}", s.auth(s.budgetsUpdate))
+	s.mux.HandleFunc("DELETE /budgets/{id}", s.auth(s.budgetsDelete))
+
+	s.mux.HandleFunc("GET /mappings", s.auth(s.mappingsList))
+	s.mux.HandleFunc("POST /mappings", s.auth(s.mappingsCreate))
+	s.mux.HandleFunc("PUT /mappings/{id}", s.auth(s.mappingsUpdate))
+	s.mux.HandleFunc("DELETE /mappings/{id}", s.auth(s.mappingsDelete))
+
+	s.mux.HandleFunc("POST /upload", s.auth(s.upload))
+
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// Adds a user, or changes their password
+func (s *Server) AddUser(name, pass string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.users[name] = pass
+}
+
+// Makes every token handed out so far invalid, as if they all expired
+func (s *Server) RevokeTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	clear(s.tokens)
+}
+
+// Shaped like the real server's UUIDs, but counting up so that tests see the same ids every time. Needs s.mu
+func (s *Server) newID() string {
+	s.lastID++
+	return fmt.Sprintf("00000000-0000-0000-0000-%012d", s.lastID)
+}
+
+// The body fetch parses into StdAPIError
+type errBody struct {
+	Error   string   `json:"error"`
+	Details []string `json:"details,omitempty"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeErr(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, errBody{Error: msg})
+}
+
+// Field problems, as field id -> message. The client turns these into a ValidationErr
+type validation [][2]string
+
+func (v *validation) add(field, msg string) {
+	*v = append(*v, [2]string{field, msg})
+}
+
+// Writes the 400 & reports true if there's anything wrong
+func (v validation) write(w http.ResponseWriter) bool {
+	if len(v) == 0 {
+		return false
+	}
+
+	details := make([]string, len(v))
+	for i, d := range v {
+		details[i] = d[0] + ": " + d[1]
+	}
+	writeJSON(w, http.StatusBadRequest, errBody{Error: "validation failed", Details: details})
+
+	return true
+}
+
+func readJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeErr(w, http.StatusBadRequest, "bad body: "+err.Error())
+		return false
+	}
+
+	return true
+}
+
+func (s *Server) login(w http.ResponseWriter, r *http.Request) {
+	var req api.ReqLogin
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if pass, ok := s.users[req.Username]; !ok || pass != req.Password {
+		writeErr(w, http.StatusUnauthorized, "wrong username or password")
+		return
+	}
+
+	now, id := time.Now(), s.newID()
+	tok, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		ID:        id,
+		Subject:   req.Username,
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(s.TokenLife)),
+	}).SignedString(s.key)
+	if err != nil {
+		writeErr(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	s.tokens[id] = true
+
+	writeJSON(w, http.StatusOK, api.RespLogin{Token: tok})
+}
+
+// Only lets requests with a valid token through. The client sends the bare token, but Bearer works too
+func (s *Server) auth(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		raw := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if raw == "" {
+			writeErr(w, http.StatusUnauthorized, "missing token")
+			return
+		}
+
+		claims := &jwt.RegisteredClaims{}
+		_, err := jwt.ParseWithClaims(raw, claims, func(*jwt.Token) (any, error) {
+			return s.key, nil
+		}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+		if err == nil {
+			s.mu.Lock()
+			if !s.tokens[claims.ID] {
+				err = errors.New("token was revoked")
+			}
+			s.mu.Unlock()
+		}
+		if err != nil {
+			writeErr(w, http.StatusUnauthorized, "bad token: "+err.Error())
+			return
+		}
+
+		next(w, r)
+	}
+}
@@ -0,0 +1,130 @@
+package fake
+
+import (
+	"net/http"
+	"regexp"
+	"slices"
+
+	"github.com/bank_data_tui/api"
+)
+
+// Adds a mapping straight away & applies it to every transaction, returning its id
+func (s *Server) AddMapping(m api.Mapping) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m.ID = s.newID()
+	s.mappings = append(s.mappings, &m)
+	s.resolveAll()
+
+	return m.ID
+}
+
+// A copy of every mapping
+func (s *Server) Mappings() []api.Mapping {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := make([]api.Mapping, len(s.mappings))
+	for i, m := range s.mappings {
+		res[i] = *m
+	}
+
+	return res
+}
+
+func (s *Server) validateMapping(m *api.Mapping) validation {
+	v := validation{}
+	if m.Name == "" {
+		v.add("name", "can't be empty")
+	}
+	if m.Priority < 0 {
+		v.add("priority", "can't be negative")
+	}
+	if _, err := regexp.CompilePOSIX(m.InpText); err != nil {
+		v.add("inpText", "not a valid regex")
+	}
+	if m.ResCategoryID != "" && s.category(m.ResCategoryID) == nil {
+		v.add("resCategory", "no category with that id")
+	}
+
+	return v
+}
+
+// Without no_retroactive, a change to the mappings is applied to every transaction right away
+func retroactive(r *http.Request) bool {
+	return r.URL.Query().Get("no_retroactive") == ""
+}
+
+func (s *Server) mappingsList(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.Mappings())
+}
+
+func (s *Server) mappingsCreate(w http.ResponseWriter, r *http.Request) {
+	var m api.Mapping
+	if !readJSON(w, r, &m) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.validateMapping(&m).write(w) {
+		return
+	}
+
+	m.ID = s.newID()
+	s.mappings = append(s.mappings, &m)
+	if retroactive(r) {
+		s.resolveAll()
+	}
+
+	writeJSON(w, http.StatusOK, api.RespCreated{ID: m.ID})
+}
+
+func (s *Server) mappingsUpdate(w http.ResponseWriter, r *http.Request) {
+	var m api.Mapping
+	if !readJSON(w, r, &m) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.validateMapping(&m).write(w) {
+		return
+	}
+
+	i := slices.IndexFunc(s.mappings, func(v *api.Mapping) bool { return v.ID == r.PathValue("id") })
+	if i == -1 {
+		writeErr(w, http.StatusNotFound, "no mapping with that id")
+		return
+	}
+
+	m.ID = s.mappings[i].ID
+	*s.mappings[i] = m
+	if retroactive(r) {
+		s.resolveAll()
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) mappingsDelete(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	i := slices.IndexFunc(s.mappings, func(v *api.Mapping) bool { return v.ID == id })
+	if i == -1 {
+		writeErr(w, http.StatusNotFound, "no mapping with that id")
+		return
+	}
+
+	s.mappings = slices.Delete(s.mappings, i, i+1)
+	if retroactive(r) {
+		s.resolveAll()
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
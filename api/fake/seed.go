@@ -0,0 +1,106 @@
+package fake
+
+import (
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"github.com/bank_data_tui/api"
+)
+
+// How far back Seed's transactions go
+const SEED_MONTHS = 6
+
+type seedMerchant struct {
+	desc     string
+	category string
+	// Typical amount, spending is negative
+	amount float64
+	// Roughly how many times a month
+	perMonth int
+}
+
+var SEED_CATEGORIES = []api.SavableCategory{
+	{Name: "Groceries", Color: "4caf50", Icon: "🛒"},
+	{Name: "Eating out", Color: "ff9800", Icon: "🍔"},
+	{Name: "Transport", Color: "2196f3", Icon: "🚆"},
+	{Name: "Bills", Color: "9c27b0", Icon: "🧾"},
+	{Name: "Fun", Color: "e91e63", Icon: "🎮"},
+	{Name: "Income", Color: "8bc34a", Icon: "💰"},
+}
+
+var SEED_MERCHANTS = []seedMerchant{
+	{"TESCO STORES 3021", "Groceries", -42.5, 5},
+	{"SAINSBURYS S/MKTS", "Groceries", -27.8, 3},
+	{"PRET A MANGER LONDON", "Eating out", -6.45, 8},
+	{"DELIVEROO.COM", "Eating out", -24.9, 2},
+	{"TFL TRAVEL CH", "Transport", -3.1, 16},
+	{"TRAINLINE.COM", "Transport", -38.4, 1},
+	{"OCTOPUS ENERGY", "Bills", -88, 1},
+	{"VIRGIN MEDIA", "Bills", -35, 1},
+	{"STEAMGAMES.COM 4259522", "Fun", -14.99, 1},
+	{"SPOTIFY P2C4F1", "Fun", -11.99, 1},
+	{"ACME LTD SALARY", "Income", 2450, 1},
+	// Nothing maps these, so there's always something uncategorised
+	{"CARD PAYMENT TO SQ *MARKET STALL", "", -9.5, 2},
+	{"AMZNMKTPLACE", "", -19.99, 2},
+}
+
+// Budgets for some of SEED_CATEGORIES, Transport's usually goes over
+var SEED_BUDGETS = []struct {
+	category string
+	api.SavableBudget
+}{
+	{"Groceries", api.SavableBudget{Period: api.BUDGET_MONTHLY, Limit: 350}},
+	{"Eating out", api.SavableBudget{Period: api.BUDGET_WEEKLY, Limit: 20}},
+	{"Transport", api.SavableBudget{Period: api.BUDGET_MONTHLY, Limit: 50}},
+}
+
+// Fills the server with a user (demo / demo-password) & a few months of made up spending up to now. The same
+// now always gives the same data
+func (s *Server) Seed(now time.Time) {
+	s.AddUser("demo", "demo-password")
+
+	catIDs := map[string]string{}
+	for _, c := range SEED_CATEGORIES {
+		catIDs[c.Name] = s.AddCategory(c)
+	}
+
+	for i, m := range SEED_MERCHANTS {
+		if m.category == "" {
+			continue
+		}
+		s.AddMapping(api.Mapping{
+			Name:          m.category + " - " + m.desc,
+			InpText:       "^" + m.desc[:min(len(m.desc), 8)],
+			ResName:       m.desc,
+			ResCategoryID: catIDs[m.category],
+			Priority:      i + 1,
+		})
+	}
+
+	rng := rand.New(rand.NewPCG(uint64(now.Year()), uint64(now.YearDay())))
+	start := now.AddDate(0, -SEED_MONTHS, 0)
+	span := now.Sub(start)
+
+	for _, m := range SEED_MERCHANTS {
+		for range m.perMonth * SEED_MONTHS {
+			authed := start.Add(time.Duration(rng.Int64N(int64(span))))
+			// Within ±30% of the usual amount, in whole pence
+			amt := math.Round(m.amount*(0.7+rng.Float64()*0.6)*100) / 100
+
+			s.AddTransaction(api.Transaction{
+				AuthedAt:  authed,
+				SettledAt: authed.Add(time.Duration(rng.IntN(72)) * time.Hour),
+				Desc:      m.desc,
+				Amount:    amt,
+			})
+		}
+	}
+
+	// Last, so that everything before keeps the same ids
+	for _, b := range SEED_BUDGETS {
+		b.CategoryID = catIDs[b.category]
+		s.AddBudget(b.SavableBudget)
+	}
+}
@@ -0,0 +1,316 @@
+package fake
+
+import (
+	"cmp"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bank_data_tui/api"
+)
+
+// Adds a transaction as if it was uploaded, returning its id. Its resolved values come from the mappings
+func (s *Server) AddTransaction(t api.Transaction) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.addTransaction(t)
+}
+
+func (s *Server) addTransaction(t api.Transaction) string {
+	t.ID = s.newID()
+	t.ResolvedName, t.ResolvedCategoryID, t.Overridden, t.Override = nil, nil, false, nil
+
+	tr := &transaction{Transaction: t}
+	s.resolve(tr)
+	s.transactions = append(s.transactions, tr)
+
+	return t.ID
+}
+
+// A copy of every transaction, in the order they were added
+func (s *Server) Transactions() []api.Transaction {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := make([]api.Transaction, len(s.transactions))
+	for i, t := range s.transactions {
+		res[i] = t.Transaction
+	}
+
+	return res
+}
+
+// Sets t's resolved values from the mappings, with its override on top
+func (s *Server) resolve(t *transaction) {
+	t.ResolvedName, t.ResolvedCategoryID = nil, nil
+	// Copies, so that later (no_retroactive) changes to the mapping don't show up here
+	if m := api.ResolvingMapping(s.mappings, &t.Transaction); m != nil {
+		if name := m.ResName; name != "" {
+			t.ResolvedName = &name
+		}
+		if cat := m.ResCategoryID; cat != "" {
+			t.ResolvedCategoryID = &cat
+		}
+	}
+
+	if t.override.Name != nil {
+		t.ResolvedName = t.override.Name
+	}
+	if t.override.CategoryID != nil {
+		t.ResolvedCategoryID = t.override.CategoryID
+	}
+	t.setOverridden()
+}
+
+// Shows what t.override is in the api's fields
+func (t *transaction) setOverridden() {
+	t.Overridden, t.Override = !t.override.Empty(), nil
+	if t.Overridden {
+		o := t.override
+		t.Override = &o
+	}
+}
+
+func (s *Server) resolveAll() {
+	for _, t := range s.transactions {
+		s.resolve(t)
+	}
+}
+
+// The query TransactionQuery.Values makes, turned back into a filter & a sort
+type txQuery struct {
+	page, pageSize int
+	order          api.TransactionFields
+	asc            bool
+
+	dateField api.TransactionFields
+	from, to  time.Time
+
+	amountMin, amountMax *float64
+
+	category string
+	desc     string
+	re       *regexp.Regexp
+	name     string
+}
+
+func parseTxQuery(q url.Values) (*txQuery, validation) {
+	v := validation{}
+	res := &txQuery{page: 1, pageSize: DEFAULT_PAGE_SIZE, order: api.TOR_AUTH, dateField: api.TOR_AUTH}
+
+	num := func(field string, dst *int, minV, maxV int) {
+		if raw := q.Get(field); raw != "" {
+			n, err := strconv.Atoi(raw)
+			if err != nil || n < minV || n > maxV {
+				v.add(field, "must be a number between "+strconv.Itoa(minV)+" and "+strconv.Itoa(maxV))
+				return
+			}
+			*dst = n
+		}
+	}
+	num("page", &res.page, 1, 1<<30)
+	num("page_size", &res.pageSize, 1, MAX_PAGE_SIZE)
+
+	if raw := q.Get("order"); raw != "" {
+		switch f := api.TransactionFields(raw); f {
+		case api.TOR_AUTH, api.TOR_SETTLE, api.TOR_AMOUNT, api.TOR_CATEGORY:
+			res.order = f
+		default:
+			v.add("order", "unknown field")
+		}
+	}
+	if raw := q.Get("asc"); raw != "" {
+		var err error
+		if res.asc, err = strconv.ParseBool(raw); err != nil {
+			v.add("asc", "must be true or false")
+		}
+	}
+
+	if raw := q.Get("date_field"); raw != "" {
+		switch f := api.TransactionFields(raw); f {
+		case api.TOR_AUTH, api.TOR_SETTLE:
+			res.dateField = f
+		default:
+			v.add("date_field", "must be authed_at or settled_at")
+		}
+	}
+	date := func(field string, dst *time.Time) {
+		if raw := q.Get(field); raw != "" {
+			t, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				v.add(field, "needs an RFC3339 date")
+				return
+			}
+			*dst = t
+		}
+	}
+	date("from", &res.from)
+	date("to", &res.to)
+
+	amount := func(field string, dst **float64) {
+		if raw := q.Get(field); raw != "" {
+			f, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				v.add(field, "needs a number")
+				return
+			}
+			*dst = &f
+		}
+	}
+	amount("amount_min", &res.amountMin)
+	amount("amount_max", &res.amountMax)
+
+	res.category = q.Get("category")
+	res.desc = strings.ToLower(q.Get("desc"))
+	res.name = strings.ToLower(q.Get("name"))
+	if raw := q.Get("desc_regex"); raw != "" {
+		var err error
+		if res.re, err = regexp.CompilePOSIX(raw); err != nil {
+			v.add("desc_regex", "not a valid regex")
+		}
+	}
+
+	return res, v
+}
+
+func (q *txQuery) matches(t *api.Transaction) bool {
+	d := t.AuthedAt
+	if q.dateField == api.TOR_SETTLE {
+		d = t.SettledAt
+	}
+	if !q.from.IsZero() && d.Before(q.from) {
+		return false
+	}
+	if !q.to.IsZero() && !d.Before(q.to) {
+		return false
+	}
+
+	if q.amountMin != nil && t.Amount < *q.amountMin {
+		return false
+	}
+	if q.amountMax != nil && t.Amount > *q.amountMax {
+		return false
+	}
+
+	switch q.category {
+	case "":
+	case api.CATEGORY_NONE:
+		if t.ResolvedCategoryID != nil {
+			return false
+		}
+	default:
+		if t.ResolvedCategoryID == nil || *t.ResolvedCategoryID != q.category {
+			return false
+		}
+	}
+
+	if q.desc != "" && !strings.Contains(strings.ToLower(t.Desc), q.desc) {
+		return false
+	}
+	if q.re != nil && !q.re.MatchString(t.Desc) {
+		return false
+	}
+	if q.name != "" && (t.ResolvedName == nil || !strings.Contains(strings.ToLower(*t.ResolvedName), q.name)) {
+		return false
+	}
+
+	return true
+}
+
+// Newest first unless asc, ties are broken by auth date & then id so that pages don't shuffle
+func (s *Server) sortTransactions(ts []api.Transaction, order api.TransactionFields, asc bool) {
+	catName := func(t *api.Transaction) string {
+		if t.ResolvedCategoryID == nil {
+			return ""
+		}
+		if c := s.category(*t.ResolvedCategoryID); c != nil {
+			return c.Name
+		}
+		return ""
+	}
+
+	slices.SortStableFunc(ts, func(a, b api.Transaction) int {
+		var r int
+		switch order {
+		case api.TOR_SETTLE:
+			r = a.SettledAt.Compare(b.SettledAt)
+		case api.TOR_AMOUNT:
+			r = cmp.Compare(a.Amount, b.Amount)
+		case api.TOR_CATEGORY:
+			r = strings.Compare(catName(&a), catName(&b))
+		}
+		if r == 0 {
+			r = a.AuthedAt.Compare(b.AuthedAt)
+		}
+		if r == 0 {
+			r = strings.Compare(a.ID, b.ID)
+		}
+
+		if !asc {
+			return -r
+		}
+		return r
+	})
+}
+
+func (s *Server) transactionsList(w http.ResponseWriter, r *http.Request) {
+	q, v := parseTxQuery(r.URL.Query())
+	if v.write(w) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	found := []api.Transaction{}
+	for _, t := range s.transactions {
+		if q.matches(&t.Transaction) {
+			found = append(found, t.Transaction)
+		}
+	}
+	s.sortTransactions(found, q.order, q.asc)
+
+	start := min(len(found), (q.page-1)*q.pageSize)
+	end := min(len(found), start+q.pageSize)
+	page := make([]*api.Transaction, 0, end-start)
+	for i := range found[start:end] {
+		page = append(page, &found[start+i])
+	}
+
+	writeJSON(w, http.StatusOK, api.RespPages[[]*api.Transaction]{Total: len(found), Data: page})
+}
+
+func (s *Server) transactionsUpdate(w http.ResponseWriter, r *http.Request) {
+	var o api.TransactionOverride
+	if !readJSON(w, r, &o) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v := validation{}
+	if o.CategoryID != nil && s.category(*o.CategoryID) == nil {
+		v.add("categoryId", "no category with that id")
+	}
+	if v.write(w) {
+		return
+	}
+
+	i := slices.IndexFunc(s.transactions, func(t *transaction) bool { return t.ID == r.PathValue("id") })
+	if i == -1 {
+		writeErr(w, http.StatusNotFound, "no transaction with that id")
+		return
+	}
+
+	t := s.transactions[i]
+	t.override = o
+	s.resolve(t)
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,105 @@
+package fake
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/bank_data_tui/api"
+)
+
+// Columns the fake understands in an upload. It's the same layout `transactions -format tsv` prints, other
+// columns are ignored
+const (
+	COL_AUTHED  = "authed_at"
+	COL_SETTLED = "settled_at"
+	COL_AMOUNT  = "amount"
+	COL_DESC    = "description"
+)
+
+func parseUploadDate(v string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+
+	return time.ParseInLocation("2006-01-02", v, time.Local)
+}
+
+// Reads a TSV with a header row. Rows are checked before anything is added, so a bad file adds nothing
+func parseUpload(r io.Reader) ([]api.Transaction, error) {
+	rd := csv.NewReader(r)
+	rd.Comma = '\t'
+	rd.LazyQuotes = true
+
+	head, err := rd.Read()
+	if err != nil {
+		return nil, fmt.Errorf("can't read the header: %w", err)
+	}
+	cols := map[string]int{}
+	for i, c := range head {
+		cols[c] = i
+	}
+	for _, c := range []string{COL_AUTHED, COL_AMOUNT, COL_DESC} {
+		if _, ok := cols[c]; !ok {
+			return nil, fmt.Errorf("missing the %s column", c)
+		}
+	}
+
+	res := []api.Transaction{}
+	for line := 2; ; line++ {
+		row, err := rd.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var t api.Transaction
+		if t.AuthedAt, err = parseUploadDate(row[cols[COL_AUTHED]]); err != nil {
+			return nil, fmt.Errorf("line %d: bad %s", line, COL_AUTHED)
+		}
+		t.SettledAt = t.AuthedAt
+		if i, ok := cols[COL_SETTLED]; ok && row[i] != "" {
+			if t.SettledAt, err = parseUploadDate(row[i]); err != nil {
+				return nil, fmt.Errorf("line %d: bad %s", line, COL_SETTLED)
+			}
+		}
+		if t.Amount, err = strconv.ParseFloat(row[cols[COL_AMOUNT]], 64); err != nil {
+			return nil, fmt.Errorf("line %d: bad %s", line, COL_AMOUNT)
+		}
+		t.Desc = row[cols[COL_DESC]]
+
+		res = append(res, t)
+	}
+
+	return res, nil
+}
+
+// Transactions already on the server (same auth date, amount & description) are skipped, so uploading
+// overlapping exports is fine
+func (s *Server) upload(w http.ResponseWriter, r *http.Request) {
+	ts, err := parseUpload(r.Body)
+	if err != nil {
+		writeErr(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, t := range ts {
+		dupe := slices.ContainsFunc(s.transactions, func(have *transaction) bool {
+			return have.AuthedAt.Equal(t.AuthedAt) && have.Amount == t.Amount && have.Desc == t.Desc
+		})
+		if !dupe {
+			s.addTransaction(t)
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -2,7 +2,9 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -13,7 +15,10 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
-const API_BASE_URL = "http://localhost:3000"
+const (
+	DEFAULT_BASE_URL = "http://localhost:3000"
+	DEFAULT_TIMEOUT  = 30 * time.Second
+)
 
 type ReqLogin struct {
 	Username string `json:"username"`
@@ -33,6 +38,21 @@ func (a APIErr) Error() string {
 	return fmt.Sprintf("%d: %s", a.Status, a.RespBody)
 }
 
+// Returned when a single request takes longer than APIClient.Timeout
+type TimeoutErr struct {
+	Method string
+	Path   string
+	After  time.Duration
+}
+
+func (t TimeoutErr) Error() string {
+	return fmt.Sprintf("%s %s timed out after %v", t.Method, t.Path, t.After)
+}
+
+func (t TimeoutErr) Unwrap() error {
+	return context.DeadlineExceeded
+}
+
 type StdAPIError struct {
 	Status  int
 	Message string   `json:"error"`
@@ -63,7 +83,46 @@ func (ValidationErr) Error() string {
 	return "Resource validation didn't go over well :("
 }
 
-func fetch[T any](method, path string, body any, authHeader string) (*T, error) {
+var ErrNotLoggedIn = errors.New("not logged in")
+
+// Returned when the client can't go on without the user logging in again. Ie. the token expired & the password
+// was changed, or the client was logged out
+type SessionExpiredErr struct {
+	Err error
+}
+
+func (s SessionExpiredErr) Error() string {
+	return "session expired: " + s.Err.Error()
+}
+
+func (s SessionExpiredErr) Unwrap() error {
+	return s.Err
+}
+
+// Status code of an error response, 0 for anything else
+func errStatus(err error) int {
+	var std *StdAPIError
+	if errors.As(err, &std) {
+		return std.Status
+	}
+	var raw *APIErr
+	if errors.As(err, &raw) {
+		return raw.Status
+	}
+
+	return 0
+}
+
+// Reports if the server actually looked at the credentials & said no, as opposed to the request failing
+func IsAuthRejection(err error) bool {
+	if s := errStatus(err); s == 401 || s == 403 {
+		return true
+	}
+	var verr *ValidationErr
+	return errors.As(err, &verr)
+}
+
+func (c *APIClient) newRequest(ctx context.Context, method, path string, body any, authHeader string) (*http.Request, error) {
 	var inp io.Reader
 	if b, ok := body.(io.ReadSeeker); ok {
 		b.Seek(0, io.SeekStart)
@@ -77,7 +136,7 @@ func fetch[T any](method, path string, body any, authHeader string) (*T, error)
 		inp = inpBuf
 	}
 
-	req, err := http.NewRequest(method, API_BASE_URL+path, inp)
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, inp)
 	if err != nil {
 		return nil, err
 	}
@@ -88,16 +147,53 @@ func fetch[T any](method, path string, body any, authHeader string) (*T, error)
 		req.Header.Set("Authorization", authHeader)
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	return req, nil
+}
+
+// If ctx is done, returns the reason for it (a TimeoutErr for our own deadline), otherwise err
+func ctxErr(ctx context.Context, err error) error {
+	if ctx.Err() == nil {
+		return err
+	}
+
+	var t *TimeoutErr
+	if cause := context.Cause(ctx); errors.As(cause, &t) {
+		return t
+	}
+
+	return ctx.Err()
+}
+
+func fetch[T any](ctx context.Context, c *APIClient, method, path string, body any, authHeader string) (*T, error) {
+	if c.Timeout != 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, c.Timeout, &TimeoutErr{
+			Method: method,
+			Path:   path,
+			After:  c.Timeout,
+		})
+		defer cancel()
+	}
+
+	req, err := c.newRequest(ctx, method, path, body, authHeader)
 	if err != nil {
 		return nil, err
 	}
 
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, ctxErr(ctx, err)
+	}
+	defer resp.Body.Close()
+
 	if resp.StatusCode != 200 && resp.StatusCode != 204 {
 		defer func() {
 			log.Printf("Bad status code '%d'", resp.StatusCode)
 		}()
-		d, _ := io.ReadAll(resp.Body)
+		d, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, ctxErr(ctx, err)
+		}
 		std := &StdAPIError{Status: resp.StatusCode}
 		if err := json.Unmarshal(d, std); err != nil {
 			return nil, &APIErr{resp.StatusCode, d}
@@ -134,14 +230,14 @@ func fetch[T any](method, path string, body any, authHeader string) (*T, error)
 	var data T
 	err = json.NewDecoder(resp.Body).Decode(&data)
 	if err != nil {
-		return nil, err
+		return nil, ctxErr(ctx, err)
 	}
 
 	return &data, nil
 }
 
-func loginReq(user string, pass string) (string, error) {
-	l, err := fetch[RespLogin](`POST`, `/login`, &ReqLogin{user, pass}, "")
+func (c *APIClient) loginReq(ctx context.Context, user string, pass string) (string, error) {
+	l, err := fetch[RespLogin](ctx, c, `POST`, `/login`, &ReqLogin{user, pass}, "")
 	if err != nil {
 		return "", err
 	}
@@ -149,53 +245,69 @@ func loginReq(user string, pass string) (string, error) {
 	return l.Token, nil
 }
 
-func loginIntoClient(c *APIClient, ovr [2]string) error {
+func loginIntoClient(ctx context.Context, c *APIClient, ovr [2]string) (*jwt.Token, error) {
 	if ovr[0] == "" {
 		ovr = c.userPass
 	}
 
-	tok, err := loginReq(ovr[0], ovr[1])
+	tok, err := c.loginReq(ctx, ovr[0], ovr[1])
 	if err != nil {
-		return err
+		return nil, err
 	}
 	parsed, _, err := jwt.NewParser().ParseUnverified(tok, jwt.MapClaims{})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	c.userPass = ovr
 	c.jwt = parsed
 
-	return nil
+	return parsed, nil
 }
 
-func easyNilFetch(c *APIClient, method, path string, body any) error {
-	_, err := easyFetch[any](c, method, path, body)
+func easyNilFetch(ctx context.Context, c *APIClient, method, path string, body any) error {
+	_, err := easyFetch[any](ctx, c, method, path, body)
 
 	return err
 }
 
-func easyFetch[T any](c *APIClient, method, path string, body any) (*T, error) {
-	if d, err := c.jwt.Claims.GetExpirationTime(); err != nil || d.Before(time.Now()) {
-		if err := loginIntoClient(c, [2]string{}); err != nil {
+// Logs in again with the kept credentials, returning the new token. If that's rejected, the session is over
+func relogin(ctx context.Context, c *APIClient) (*jwt.Token, error) {
+	if c.userPass[0] == "" || c.userPass[1] == "" {
+		return nil, &SessionExpiredErr{Err: ErrTokenExpired}
+	}
+
+	tok, err := loginIntoClient(ctx, c, [2]string{})
+	if err != nil && IsAuthRejection(err) {
+		return nil, &SessionExpiredErr{Err: err}
+	}
+
+	return tok, err
+}
+
+func easyFetch[T any](ctx context.Context, c *APIClient, method, path string, body any) (*T, error) {
+	// Kept in a local, since a logout can happen while this is running
+	tok := c.jwt
+	if tok == nil {
+		return nil, &SessionExpiredErr{Err: ErrNotLoggedIn}
+	}
+
+	if d, err := tok.Claims.GetExpirationTime(); err != nil || d.Before(time.Now()) {
+		if tok, err = relogin(ctx, c); err != nil {
 			return nil, err
 		}
 	}
 
-	t, err := fetch[T](method, path, body, c.jwt.Raw)
-	if err != nil {
-		if e, ok := err.(*APIErr); ok && e.Status == 401 {
-			if err := loginIntoClient(c, [2]string{}); err != nil {
-				return nil, err
-			}
-
-			t, err = fetch[T](method, path, body, c.jwt.Raw)
-			if err != nil {
-				return nil, err
-			}
-		} else {
+	t, err := fetch[T](ctx, c, method, path, body, tok.Raw)
+	if err != nil && errStatus(err) == 401 {
+		if tok, err = relogin(ctx, c); err != nil {
 			return nil, err
 		}
+
+		t, err = fetch[T](ctx, c, method, path, body, tok.Raw)
+	}
+	if err != nil {
+		return nil, err
 	}
 
 	return t, nil
@@ -209,12 +321,99 @@ func deArray[T any](v *[]T, err error) ([]T, error) {
 }
 
 type APIClient struct {
+	// Base URL of the server, without a trailing /
+	BaseURL string
+	// Time limit for a single request (including reading the body), 0 means no limit
+	Timeout time.Duration
+	HTTP    *http.Client
+
 	userPass [2]string
 	jwt      *jwt.Token
 }
 
-func (a *APIClient) Login(userAndPass [2]string) error {
-	return loginIntoClient(a, userAndPass)
+type ClientOption func(c *APIClient)
+
+func WithBaseURL(u string) ClientOption {
+	return func(c *APIClient) {
+		c.BaseURL = u
+	}
+}
+
+func WithTimeout(d time.Duration) ClientOption {
+	return func(c *APIClient) {
+		c.Timeout = d
+	}
+}
+
+func WithHTTPClient(h *http.Client) ClientOption {
+	return func(c *APIClient) {
+		c.HTTP = h
+	}
+}
+
+func NewClient(opts ...ClientOption) *APIClient {
+	c := &APIClient{
+		BaseURL: DEFAULT_BASE_URL,
+		Timeout: DEFAULT_TIMEOUT,
+	}
+
+	for _, o := range opts {
+		o(c)
+	}
+
+	c.BaseURL = strings.TrimSuffix(c.BaseURL, "/")
+	if c.HTTP == nil {
+		c.HTTP = http.DefaultClient
+	}
+
+	return c
+}
+
+func (a *APIClient) Login(ctx context.Context, userAndPass [2]string) error {
+	_, err := loginIntoClient(ctx, a, userAndPass)
+	return err
+}
+
+var ErrTokenExpired = errors.New("token expired")
+
+// Logs in with a token from an earlier login, if it's still valid. Otherwise, or once it expires, logs in with
+// userAndPass. Without them (ie. empty), ErrTokenExpired is returned instead
+func (a *APIClient) Resume(ctx context.Context, tok string, userAndPass [2]string) error {
+	parsed, _, err := jwt.NewParser().ParseUnverified(tok, jwt.MapClaims{})
+	if err == nil {
+		if d, err := parsed.Claims.GetExpirationTime(); err == nil && d != nil && d.After(time.Now()) {
+			a.jwt = parsed
+			a.userPass = userAndPass
+			return nil
+		}
+	}
+
+	if userAndPass[0] == "" || userAndPass[1] == "" {
+		return ErrTokenExpired
+	}
+
+	_, err = loginIntoClient(ctx, a, userAndPass)
+	return err
+}
+
+// The current token, "" when logged out
+func (a *APIClient) Token() string {
+	if a.jwt == nil {
+		return ""
+	}
+
+	return a.jwt.Raw
+}
+
+// Who the client logged in as, "" if it doesn't know
+func (a *APIClient) Username() string {
+	return a.userPass[0]
+}
+
+// Forgets the token & credentials
+func (a *APIClient) Logout() {
+	a.jwt = nil
+	a.userPass = [2]string{}
 }
 
 type RespCreated struct {
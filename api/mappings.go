@@ -1,5 +1,12 @@
 package api
 
+import (
+	"context"
+	"math"
+	"regexp"
+	"sync"
+)
+
 type Mapping struct {
 	ID   string `json:"id,omitempty"`
 	Name string `json:"name"`
@@ -13,17 +20,17 @@ type Mapping struct {
 	Priority int `json:"priority"`
 }
 
-func (c *APIClient) MappingsFetch() ([]*Mapping, error) {
-	return deArray(easyFetch[[]*Mapping](c, `GET`, `/mappings`, nil))
+func (c *APIClient) MappingsFetch(ctx context.Context) ([]*Mapping, error) {
+	return deArray(easyFetch[[]*Mapping](ctx, c, `GET`, `/mappings`, nil))
 }
 
-func (c *APIClient) MappingsCreate(s *Mapping, noRetroactive bool) (string, error) {
+func (c *APIClient) MappingsCreate(ctx context.Context, s *Mapping, noRetroactive bool) (string, error) {
 	q := ""
 	if noRetroactive {
 		q += "?no_retroactive=1"
 	}
 
-	resp, err := easyFetch[RespCreated](c, `POST`, `/mappings` + q, s)
+	resp, err := easyFetch[RespCreated](ctx, c, `POST`, `/mappings` + q, s)
 	if err != nil {
 		return "", err
 	}
@@ -31,20 +38,99 @@ func (c *APIClient) MappingsCreate(s *Mapping, noRetroactive bool) (string, erro
 	return resp.ID, nil
 }
 
-func (c *APIClient) MappingsUpdate(id string, s *Mapping, noRetroactive bool) error {
+func (c *APIClient) MappingsUpdate(ctx context.Context, id string, s *Mapping, noRetroactive bool) error {
 	q := ""
 	if noRetroactive {
 		q += "?no_retroactive=1"
 	}
 
-	return easyNilFetch(c, `PUT`, `/mappings/` + id + q, s)
+	return easyNilFetch(ctx, c, `PUT`, `/mappings/` + id + q, s)
 }
 
-func (c *APIClient) MappingsDelete(id string, noRetroactive bool) error {
+func (c *APIClient) MappingsDelete(ctx context.Context, id string, noRetroactive bool) error {
 	q := ""
 	if noRetroactive {
 		q += "?no_retroactive=1"
 	}
 
-	return easyNilFetch(c, `DELETE`, `/mappings/` + id + q, nil)
+	return easyNilFetch(ctx, c, `DELETE`, `/mappings/` + id + q, nil)
+}
+
+// Compiled matcher regexes, keyed by their source
+var mappingRegexes sync.Map
+
+func compileMatcher(src string) (*regexp.Regexp, error) {
+	if re, ok := mappingRegexes.Load(src); ok {
+		return re.(*regexp.Regexp), nil
+	}
+
+	re, err := regexp.CompilePOSIX(src)
+	if err != nil {
+		return nil, err
+	}
+	mappingRegexes.Store(src, re)
+
+	return re, nil
+}
+
+func matches(re *regexp.Regexp, amt *float64, t *Transaction) bool {
+	if re == nil && amt == nil {
+		return false
+	}
+	// amounts are in cents, so anything smaller than half a cent is float noise
+	if amt != nil && math.Abs(*amt-t.Amount) >= 0.005 {
+		return false
+	}
+	if re != nil && !re.MatchString(t.Desc) {
+		return false
+	}
+
+	return true
+}
+
+// Reports if the mapping would apply to t. Mappings without a matcher, or with an invalid regex, never match
+func (m *Mapping) Matches(t *Transaction) bool {
+	var re *regexp.Regexp
+	if m.InpText != "" {
+		var err error
+		re, err = compileMatcher(m.InpText)
+		if err != nil {
+			return false
+		}
+	}
+
+	return matches(re, m.InpAmt, t)
+}
+
+// Same as Matches, but compiled once up front & without caching the regex. Meant for mappings that are still being edited
+func (m *Mapping) Matcher() (func(t *Transaction) bool, error) {
+	var re *regexp.Regexp
+	if m.InpText != "" {
+		var err error
+		re, err = regexp.CompilePOSIX(m.InpText)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	amt := m.InpAmt
+
+	return func(t *Transaction) bool {
+		return matches(re, amt, t)
+	}, nil
+}
+
+// The mapping that wins for t (highest priority that matches), or nil
+func ResolvingMapping(ms []*Mapping, t *Transaction) *Mapping {
+	var best *Mapping
+	for _, m := range ms {
+		if !m.Matches(t) {
+			continue
+		}
+		if best == nil || m.Priority > best.Priority {
+			best = m
+		}
+	}
+
+	return best
 }
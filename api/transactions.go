@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"net/url"
 	"strconv"
 	"time"
@@ -14,6 +15,20 @@ type Transaction struct {
 	Amount             float64   `json:"amount"`
 	ResolvedName       *string   `json:"resolvedName,omitempty"`
 	ResolvedCategoryID *string   `json:"resolvedCategoryId,omitempty"`
+	// Set when the resolved values were set by hand instead of by a mapping
+	Overridden bool `json:"overridden,omitempty"`
+	// Which of the resolved values were set by hand. nil when not Overridden, or when the server doesn't say
+	Override *TransactionOverride `json:"override,omitempty"`
+}
+
+// Manual resolved values for a single transaction, nil fields fall back to the mappings
+type TransactionOverride struct {
+	Name       *string `json:"name"`
+	CategoryID *string `json:"categoryId"`
+}
+
+func (o TransactionOverride) Empty() bool {
+	return o.Name == nil && o.CategoryID == nil
 }
 
 type TransactionFields string
@@ -25,15 +40,107 @@ const (
 	TOR_CATEGORY TransactionFields = "category"
 )
 
-func (c *APIClient) TransactionsFetch(orderBy TransactionFields, page int, asc bool) (*RespPages[[]*Transaction], error) {
-	q := url.Values{}
-	q.Set("page", strconv.Itoa(page))
-	q.Set("order", string(orderBy))
-	if asc {
-		q.Set("asc", "true")
-	} else {
-		q.Set("asc", "false")
+// Value for TransactionQuery.CategoryID that matches transactions without a category
+const CATEGORY_NONE = "none"
+
+type TransactionQuery struct {
+	Page int
+	// Transactions per page, 0 leaves it up to the server
+	PageSize int
+	OrderBy  TransactionFields
+	Asc      bool
+
+	// Which date From & To apply to, TOR_AUTH or TOR_SETTLE. Defaults to TOR_AUTH
+	DateField TransactionFields
+	// Inclusive, ignored if zero
+	From time.Time
+	// Exclusive, ignored if zero
+	To time.Time
+
+	AmountMin *float64
+	AmountMax *float64
+
+	// Category ID, or CATEGORY_NONE for uncategorised ones
+	CategoryID string
+
+	// Case insensitive substring of the raw description
+	DescContains string
+	// POSIX regex on the raw description
+	DescRegex string
+	// Case insensitive substring of the resolved name
+	ResolvedName string
+}
+
+// Reports if any filter (as opposed to paging/ordering) is set
+func (q TransactionQuery) Filtered() bool {
+	return !q.From.IsZero() || !q.To.IsZero() ||
+		q.AmountMin != nil || q.AmountMax != nil ||
+		q.CategoryID != "" ||
+		q.DescContains != "" || q.DescRegex != "" ||
+		q.ResolvedName != ""
+}
+
+func (q TransactionQuery) Values() url.Values {
+	v := url.Values{}
+
+	page := q.Page
+	if page == 0 {
+		page = 1
+	}
+	v.Set("page", strconv.Itoa(page))
+	if q.PageSize != 0 {
+		v.Set("page_size", strconv.Itoa(q.PageSize))
+	}
+
+	order := q.OrderBy
+	if order == "" {
+		order = TOR_AUTH
 	}
+	v.Set("order", string(order))
+	v.Set("asc", strconv.FormatBool(q.Asc))
+
+	if !q.From.IsZero() || !q.To.IsZero() {
+		f := q.DateField
+		if f == "" {
+			f = TOR_AUTH
+		}
+		v.Set("date_field", string(f))
+	}
+	if !q.From.IsZero() {
+		v.Set("from", q.From.Format(time.RFC3339))
+	}
+	if !q.To.IsZero() {
+		v.Set("to", q.To.Format(time.RFC3339))
+	}
+
+	if q.AmountMin != nil {
+		v.Set("amount_min", strconv.FormatFloat(*q.AmountMin, 'f', -1, 64))
+	}
+	if q.AmountMax != nil {
+		v.Set("amount_max", strconv.FormatFloat(*q.AmountMax, 'f', -1, 64))
+	}
+
+	if q.CategoryID != "" {
+		v.Set("category", q.CategoryID)
+	}
+	if q.DescContains != "" {
+		v.Set("desc", q.DescContains)
+	}
+	if q.DescRegex != "" {
+		v.Set("desc_regex", q.DescRegex)
+	}
+	if q.ResolvedName != "" {
+		v.Set("name", q.ResolvedName)
+	}
+
+	return v
+}
+
+func (c *APIClient) TransactionsFetch(ctx context.Context, q TransactionQuery) (*RespPages[[]*Transaction], error) {
+	return easyFetch[RespPages[[]*Transaction]](ctx, c, `GET`, `/transactions?`+q.Values().Encode(), nil)
+}
 
-	return easyFetch[RespPages[[]*Transaction]](c, `GET`, `/transactions?`+q.Encode(), nil)
+// Sets (or with an empty override, clears) the manual name & category of a transaction
+func (c *APIClient) TransactionUpdate(ctx context.Context, id string, o *TransactionOverride) error {
+	return easyNilFetch(ctx, c, `PUT`, `/transactions/`+id, o)
 }
@@ -1,7 +1,10 @@
 package api
 
-import "io"
+import (
+	"context"
+	"io"
+)
 
-func (a *APIClient) UploadTSV(f io.ReadSeeker) error {
-	return easyNilFetch(a, `POST`, `/upload`, f)
+func (a *APIClient) UploadTSV(ctx context.Context, f io.ReadSeeker) error {
+	return easyNilFetch(ctx, a, `POST`, `/upload`, f)
 }
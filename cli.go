@@ -0,0 +1,395 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bank_data_tui/api"
+	"github.com/bank_data_tui/utils/backup"
+)
+
+// Exit codes for the non-interactive commands
+const (
+	EXIT_OK    = 0
+	EXIT_FAIL  = 1
+	EXIT_USAGE = 2
+	// Missing or rejected credentials
+	EXIT_AUTH = 3
+)
+
+const CLI_DATE_FORMAT = "2006-01-02"
+
+const CLI_USAGE = `Usage: bank_data_tui [flags] [command]
+
+Without a command, the interactive UI is started. Commands:
+  upload <file.tsv>                   upload a bank export
+  transactions [flags]                print transactions, see transactions -h
+  mappings export [-format yaml|json] print every mapping & category as an export document
+  categories list [-format tsv|csv|json]
+
+Commands log in with the saved session, or USERNAME & PASSWORD (env or .env).
+`
+
+// Usage mistakes, so that the exit code can tell them apart from the server failing
+type usageErr struct{ error }
+
+func usagef(format string, a ...any) error {
+	return usageErr{fmt.Errorf(format, a...)}
+}
+
+// Missing or rejected credentials
+type authErr struct{ error }
+
+// Logs in like the UI does, with the saved session & then the env credentials. Commands call this after their
+// args check out
+func cliLogin(ctx context.Context, c *api.APIClient, s *sessionStore) error {
+	restored, err := s.restore(ctx, c)
+	if err != nil {
+		// The env might still work
+		fmt.Fprintln(os.Stderr, "Can't restore the session:", err)
+	}
+	if restored {
+		return nil
+	}
+
+	creds, ok := envCredentials()
+	if !ok {
+		return authErr{errors.New("USERNAME and PASSWORD need to be set")}
+	}
+
+	err = c.Login(ctx, creds)
+	if err != nil && api.IsAuthRejection(err) {
+		return authErr{fmt.Errorf("can't login: %w", err)}
+	} else if err != nil {
+		return err
+	}
+	// Saved by runCLI once the command is done, as the UI does on exit
+	s.creds = creds
+
+	return nil
+}
+
+type cliCommand func(ctx context.Context, c *api.APIClient, s *sessionStore, args []string, out io.Writer) error
+
+var CLI_COMMANDS = map[string]cliCommand{
+	"upload":       cliUpload,
+	"transactions": cliTransactions,
+	"mappings":     cliMappings,
+	"categories":   cliCategories,
+}
+
+func runCLI(ctx context.Context, c *api.APIClient, s *sessionStore, args []string) int {
+	cmd, ok := CLI_COMMANDS[args[0]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Unknown command %q\n\n%s", args[0], CLI_USAGE)
+		return EXIT_USAGE
+	}
+
+	err := cmd(ctx, c, s, args[1:], os.Stdout)
+	s.close(c)
+	if err == nil {
+		return EXIT_OK
+	}
+
+	if errors.Is(err, flag.ErrHelp) {
+		return EXIT_OK
+	}
+
+	fmt.Fprintln(os.Stderr, err)
+	if errors.As(err, &usageErr{}) {
+		return EXIT_USAGE
+	}
+	var expired *api.SessionExpiredErr
+	if errors.As(err, &authErr{}) || errors.As(err, &expired) {
+		return EXIT_AUTH
+	}
+
+	return EXIT_FAIL
+}
+
+// A flag set that reports problems as usageErr instead of exiting
+func cliFlags(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+
+	return fs
+}
+
+func parseCLIFlags(fs *flag.FlagSet, args []string) error {
+	err := fs.Parse(args)
+	if err != nil && !errors.Is(err, flag.ErrHelp) {
+		return usageErr{err}
+	}
+
+	return err
+}
+
+func cliUpload(ctx context.Context, c *api.APIClient, s *sessionStore, args []string, out io.Writer) error {
+	if len(args) != 1 {
+		return usagef("upload needs exactly 1 file")
+	}
+
+	f, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := cliLogin(ctx, c, s); err != nil {
+		return err
+	}
+
+	return c.UploadTSV(ctx, f)
+}
+
+func cliTransactions(ctx context.Context, c *api.APIClient, s *sessionStore, args []string, out io.Writer) error {
+	fs := cliFlags("transactions")
+	from := fs.String("from", "", "only transactions on or after this date (YYYY-MM-DD)")
+	to := fs.String("to", "", "only transactions on or before this date (YYYY-MM-DD)")
+	dateField := fs.String("date", "auth", "which date -from & -to use, auth or settle")
+	minAmt := fs.String("min", "", "minimum amount")
+	maxAmt := fs.String("max", "", "maximum amount")
+	category := fs.String("category", "", "category name, or none for uncategorised ones")
+	desc := fs.String("desc", "", "description contains")
+	re := fs.String("regex", "", "description matches this regex")
+	name := fs.String("name", "", "resolved name contains")
+	sort := fs.String("sort", "auth", "auth, settle, amount or category")
+	asc := fs.Bool("asc", false, "sort ascending")
+	format := fs.String("format", "csv", "csv, tsv or json")
+
+	if err := parseCLIFlags(fs, args); err != nil {
+		return err
+	}
+
+	q := api.TransactionQuery{
+		Asc:          *asc,
+		DescContains: *desc,
+		DescRegex:    *re,
+		ResolvedName: *name,
+	}
+
+	var err error
+	if q.OrderBy, err = cliTransactionField("sort", *sort, true); err != nil {
+		return err
+	}
+	if q.DateField, err = cliTransactionField("date", *dateField, false); err != nil {
+		return err
+	}
+	if q.From, err = cliDate("from", *from); err != nil {
+		return err
+	}
+	if q.To, err = cliDate("to", *to); err != nil {
+		return err
+	}
+	if !q.To.IsZero() {
+		// The api's To is exclusive
+		q.To = q.To.AddDate(0, 0, 1)
+	}
+	if q.AmountMin, err = cliAmount("min", *minAmt); err != nil {
+		return err
+	}
+	if q.AmountMax, err = cliAmount("max", *maxAmt); err != nil {
+		return err
+	}
+	if *format != "csv" && *format != "tsv" && *format != "json" {
+		return usagef("-format: must be csv, tsv or json")
+	}
+
+	if err := cliLogin(ctx, c, s); err != nil {
+		return err
+	}
+
+	cats, err := c.CategoriesFetch(ctx)
+	if err != nil {
+		return err
+	}
+	catNames := map[string]string{}
+	for _, v := range cats {
+		catNames[v.ID] = v.Name
+		// Like the filter bar, ie. the first one with that name in any case
+		if q.CategoryID == "" && strings.EqualFold(v.Name, *category) {
+			q.CategoryID = v.ID
+		}
+	}
+	if strings.EqualFold(*category, api.CATEGORY_NONE) {
+		q.CategoryID = api.CATEGORY_NONE
+	} else if *category != "" && q.CategoryID == "" {
+		return usagef("-category: no category called %q", *category)
+	}
+
+	all := []*api.Transaction{}
+	for q.Page = 1; ; q.Page++ {
+		d, err := c.TransactionsFetch(ctx, q)
+		if err != nil {
+			return err
+		}
+
+		all = append(all, d.Data...)
+		if len(d.Data) == 0 || len(all) >= d.Total {
+			break
+		}
+	}
+
+	if *format == "json" {
+		return writeJSON(out, all)
+	}
+
+	w := csv.NewWriter(out)
+	if *format == "tsv" {
+		w.Comma = '\t'
+	}
+
+	w.Write([]string{"id", "authed_at", "settled_at", "amount", "description", "name", "category"})
+	for _, t := range all {
+		name, cat := "", ""
+		if t.ResolvedName != nil {
+			name = *t.ResolvedName
+		}
+		if t.ResolvedCategoryID != nil {
+			cat = catNames[*t.ResolvedCategoryID]
+		}
+
+		w.Write([]string{
+			t.ID,
+			t.AuthedAt.Format(time.RFC3339),
+			t.SettledAt.Format(time.RFC3339),
+			strconv.FormatFloat(t.Amount, 'f', 2, 64),
+			t.Desc,
+			name,
+			cat,
+		})
+	}
+	w.Flush()
+
+	return w.Error()
+}
+
+func cliTransactionField(flagName, v string, allowAll bool) (api.TransactionFields, error) {
+	switch v {
+	case "auth":
+		return api.TOR_AUTH, nil
+	case "settle":
+		return api.TOR_SETTLE, nil
+	case "amount", "category":
+		if allowAll {
+			return api.TransactionFields(v), nil
+		}
+	}
+
+	return "", usagef("-%s: unknown value %q", flagName, v)
+}
+
+func cliDate(flagName, v string) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+
+	t, err := time.ParseInLocation(CLI_DATE_FORMAT, v, time.Local)
+	if err != nil {
+		return time.Time{}, usagef("-%s: needs a YYYY-MM-DD date", flagName)
+	}
+
+	return t, nil
+}
+
+func cliAmount(flagName, v string) (*float64, error) {
+	if v == "" {
+		return nil, nil
+	}
+
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return nil, usagef("-%s: needs a number", flagName)
+	}
+
+	return &f, nil
+}
+
+func cliMappings(ctx context.Context, c *api.APIClient, s *sessionStore, args []string, out io.Writer) error {
+	if len(args) == 0 || args[0] != "export" {
+		return usagef("mappings: only export is supported")
+	}
+
+	fs := cliFlags("mappings export")
+	format := fs.String("format", "yaml", "yaml or json")
+	if err := parseCLIFlags(fs, args[1:]); err != nil {
+		return err
+	}
+	if *format != string(backup.FORMAT_YAML) && *format != string(backup.FORMAT_JSON) {
+		return usagef("-format: must be yaml or json")
+	}
+
+	if err := cliLogin(ctx, c, s); err != nil {
+		return err
+	}
+
+	doc, err := backup.Export(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	data, err := doc.Encode(backup.Format(*format))
+	if err != nil {
+		return err
+	}
+
+	_, err = out.Write(data)
+	return err
+}
+
+func cliCategories(ctx context.Context, c *api.APIClient, s *sessionStore, args []string, out io.Writer) error {
+	if len(args) == 0 || args[0] != "list" {
+		return usagef("categories: only list is supported")
+	}
+
+	fs := cliFlags("categories list")
+	format := fs.String("format", "tsv", "tsv, csv or json")
+	if err := parseCLIFlags(fs, args[1:]); err != nil {
+		return err
+	}
+	if *format != "csv" && *format != "tsv" && *format != "json" {
+		return usagef("-format: must be tsv, csv or json")
+	}
+
+	if err := cliLogin(ctx, c, s); err != nil {
+		return err
+	}
+
+	cats, err := c.CategoriesFetch(ctx)
+	if err != nil {
+		return err
+	}
+
+	if *format == "json" {
+		return writeJSON(out, cats)
+	}
+
+	w := csv.NewWriter(out)
+	if *format == "tsv" {
+		w.Comma = '\t'
+	}
+
+	w.Write([]string{"id", "name", "color", "icon"})
+	for _, v := range cats {
+		w.Write([]string{v.ID, v.Name, v.Color, v.Icon})
+	}
+	w.Flush()
+
+	return w.Error()
+}
+
+func writeJSON(out io.Writer, v any) error {
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(v)
+}
@@ -0,0 +1,42 @@
+// Runs the in-memory fake backend, for trying the TUI out without the real server
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/bank_data_tui/api/fake"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:3000", "Address to listen on")
+	user := flag.String("user", "", "Extra user to add, as username:password")
+	empty := flag.Bool("empty", false, "Start without the demo user & data")
+	tokenLife := flag.Duration("token-life", fake.DEFAULT_TOKEN_LIFE, "How long login tokens last")
+	flag.Parse()
+
+	s := fake.New()
+	s.TokenLife = *tokenLife
+	if !*empty {
+		s.Seed(time.Now())
+	}
+	if *user != "" {
+		name, pass, ok := strings.Cut(*user, ":")
+		if !ok || name == "" || pass == "" {
+			fmt.Fprintln(os.Stderr, "-user needs to be username:password")
+			os.Exit(2)
+		}
+		s.AddUser(name, pass)
+	}
+
+	log.Printf("Fake server on http://%s", *addr)
+	if !*empty {
+		log.Println("Log in with demo / demo-password")
+	}
+	log.Fatal(http.ListenAndServe(*addr, s))
+}
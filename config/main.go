@@ -0,0 +1,231 @@
+// The user's config file. Everything in it is optional, missing values fall back to Default()
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bank_data_tui/api"
+	"github.com/bank_data_tui/styles"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	DIR_NAME  = "bank_data_tui"
+	FILE_NAME = "config.yaml"
+
+	DEFAULT_PAGE_SIZE = 50
+	MAX_PAGE_SIZE     = 500
+
+	SESSION_FILE_NAME = "session"
+)
+
+// What session.store can be
+const (
+	STORE_NONE     = "none"
+	STORE_TOKEN    = "token"
+	STORE_PASSWORD = "password"
+)
+
+// Names usable in default_screen
+var SCREENS = []string{"dashboard", "reports", "transactions", "mappings", "categories", "budgets", "upload"}
+
+// Names usable in transactions.sort, mapped to the api's fields
+var SORT_FIELDS = map[string]api.TransactionFields{
+	"auth":     api.TOR_AUTH,
+	"settle":   api.TOR_SETTLE,
+	"amount":   api.TOR_AMOUNT,
+	"category": api.TOR_CATEGORY,
+}
+
+type Transactions struct {
+	// How many transactions to ask the server for at once
+	PageSize int    `yaml:"page_size"`
+	Sort     string `yaml:"sort"`
+	Asc      bool   `yaml:"asc"`
+}
+
+type Amount struct {
+	Decimals int `yaml:"decimals"`
+	// Eg. £ or €
+	Prefix    string `yaml:"prefix"`
+	Suffix    string `yaml:"suffix"`
+	Thousands string `yaml:"thousands"`
+}
+
+type Session struct {
+	// What is kept between runs: none, token, or password (which also keeps the token)
+	Store string `yaml:"store"`
+	// Where it's kept, encrypted with a passphrase
+	File string `yaml:"file"`
+}
+
+type Config struct {
+	Server  string        `yaml:"server"`
+	Timeout time.Duration `yaml:"timeout"`
+	Session Session       `yaml:"session"`
+
+	DefaultScreen string       `yaml:"default_screen"`
+	Transactions  Transactions `yaml:"transactions"`
+
+	// Go time layout, eg. 02/01/2006
+	DateFormat string       `yaml:"date_format"`
+	Amount     Amount       `yaml:"amount"`
+	Theme      styles.Theme `yaml:"theme"`
+
+	// group.action -> keys. Replaces the default keys of that action, see keymap
+	Keys map[string][]string `yaml:"keys"`
+
+	// Where the log file is written to
+	Log string `yaml:"log"`
+}
+
+func Default() *Config {
+	return &Config{
+		Server:  api.DEFAULT_BASE_URL,
+		Timeout: api.DEFAULT_TIMEOUT,
+		Session: Session{
+			Store: STORE_NONE,
+			File:  filepath.Join(filepath.Dir(Path()), SESSION_FILE_NAME),
+		},
+		DefaultScreen: "transactions",
+		Transactions: Transactions{
+			PageSize: DEFAULT_PAGE_SIZE,
+			Sort:     "auth",
+		},
+		DateFormat: "02/01/2006",
+		Amount:     Amount{Decimals: 2},
+		Theme:      styles.DEFAULT_THEME,
+		Keys:       map[string][]string{},
+		Log:        "logs/log.log",
+	}
+}
+
+// Default location, under the XDG config dir
+func Path() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return FILE_NAME
+	}
+
+	return filepath.Join(dir, DIR_NAME, FILE_NAME)
+}
+
+// Reads the config at p on top of the defaults. A missing file is not an error
+func Load(p string) (*Config, error) {
+	c := Default()
+
+	f, err := os.Open(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	// Catches typos, which would otherwise silently do nothing
+	dec.KnownFields(true)
+	if err := dec.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	return c, c.Validate()
+}
+
+// Every problem with the config, joined
+func (c *Config) Validate() error {
+	errs := []error{}
+	bad := func(field, format string, a ...any) {
+		errs = append(errs, fmt.Errorf("%s: "+format, append([]any{field}, a...)...))
+	}
+
+	if c.Server == "" {
+		bad("server", "can't be empty")
+	}
+	if c.Timeout < 0 {
+		bad("timeout", "can't be negative")
+	}
+	if c.Session.Store != STORE_NONE && c.Session.Store != STORE_TOKEN && c.Session.Store != STORE_PASSWORD {
+		bad("session.store", "must be one of none, token, password")
+	}
+	if c.Session.File == "" {
+		bad("session.file", "can't be empty")
+	}
+	if !slices.Contains(SCREENS, c.DefaultScreen) {
+		bad("default_screen", "must be one of %s", strings.Join(SCREENS, ", "))
+	}
+	if c.Transactions.PageSize < 1 || c.Transactions.PageSize > MAX_PAGE_SIZE {
+		bad("transactions.page_size", "must be between 1 and %d", MAX_PAGE_SIZE)
+	}
+	if _, ok := SORT_FIELDS[c.Transactions.Sort]; !ok {
+		bad("transactions.sort", "must be one of auth, settle, amount, category")
+	}
+
+	// A layout without anything in it formats to itself
+	ref := time.Date(2001, 3, 4, 5, 6, 7, 0, time.UTC)
+	if c.DateFormat == "" || ref.Format(c.DateFormat) == c.DateFormat {
+		bad("date_format", "%q isn't a Go time layout (eg. 02/01/2006)", c.DateFormat)
+	}
+	if c.Amount.Decimals < 0 || c.Amount.Decimals > 6 {
+		bad("amount.decimals", "must be between 0 and 6")
+	}
+	if err := c.Theme.Validate(); err != nil {
+		bad("theme", "%v", err)
+	}
+	for action, keys := range c.Keys {
+		if len(keys) == 0 {
+			bad("keys."+action, "needs at least 1 key")
+		}
+	}
+	if c.Log == "" {
+		bad("log", "can't be empty")
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c *Config) SortField() api.TransactionFields {
+	return SORT_FIELDS[c.Transactions.Sort]
+}
+
+func (c *Config) FormatDate(t time.Time) string {
+	return t.Local().Format(c.DateFormat)
+}
+
+func (a Amount) Format(v float64) string {
+	sign := ""
+	if v < 0 {
+		sign = "-"
+		v = -v
+	}
+
+	s := strconv.FormatFloat(v, 'f', a.Decimals, 64)
+	if a.Thousands != "" {
+		whole, frac, hasFrac := strings.Cut(s, ".")
+		for i := len(whole) - 3; i > 0; i -= 3 {
+			whole = whole[:i] + a.Thousands + whole[i:]
+		}
+
+		s = whole
+		if hasFrac {
+			s += "." + frac
+		}
+	}
+
+	return sign + a.Prefix + s + a.Suffix
+}
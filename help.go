@@ -0,0 +1,172 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/bank_data_tui/styles"
+	"github.com/bank_data_tui/utils"
+	"github.com/bank_data_tui/utils/keymap"
+)
+
+const (
+	HELP_MAX_WIDTH = 64
+	// Space between the overlay & the edge of the screen
+	HELP_MARGIN = 2
+	// Border + title + empty line
+	HELP_CHROME_H = 2 + 2
+)
+
+var (
+	KEY_HELP_CLOSE     = key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "close help"))
+	KEY_HELP_UP        = key.NewBinding(key.WithKeys("up"), key.WithHelp("↑", "scroll up"))
+	KEY_HELP_DOWN      = key.NewBinding(key.WithKeys("down"), key.WithHelp("↓", "scroll down"))
+	KEY_HELP_PAGE_UP   = key.NewBinding(key.WithKeys("pgup"), key.WithHelp("page up", "scroll a page up"))
+	KEY_HELP_PAGE_DOWN = key.NewBinding(key.WithKeys("pgdown"), key.WithHelp("page down", "scroll a page down"))
+)
+
+func init() {
+	keymap.Register("help", map[string]*key.Binding{
+		"close":     &KEY_HELP_CLOSE,
+		"up":        &KEY_HELP_UP,
+		"down":      &KEY_HELP_DOWN,
+		"page_up":   &KEY_HELP_PAGE_UP,
+		"page_down": &KEY_HELP_PAGE_DOWN,
+	})
+}
+
+type helpOverlay struct {
+	open bool
+	off  int
+}
+
+type helpSection struct {
+	group string
+	keys  []key.Binding
+}
+
+// If msg should be typed into the screen, instead of opening the help
+func (m mainApp) typesText(msg tea.KeyPressMsg) bool {
+	t, ok := m.cur().(utils.Typer)
+	return ok && t.Typing() && msg.Text != ""
+}
+
+// The screen's keys, then the global ones. Sections are titled by keymap group, which is also what the config uses
+func (m mainApp) helpSections() []helpSection {
+	sections := []helpSection{}
+	if h, ok := m.cur().(utils.KeyHelper); ok {
+		for _, b := range h.KeyHelp() {
+			g := keymap.GroupOf(b)
+			i := slices.IndexFunc(sections, func(s helpSection) bool { return s.group == g })
+			if i == -1 {
+				sections = append(sections, helpSection{group: g})
+				i = len(sections) - 1
+			}
+			sections[i].keys = append(sections[i].keys, b)
+		}
+	}
+
+	return append(sections, helpSection{group: keymap.GROUP_GLOBAL, keys: keymap.Group(keymap.GROUP_GLOBAL)})
+}
+
+func (m mainApp) helpLines() []string {
+	sections := m.helpSections()
+
+	keyW := 0
+	for _, s := range sections {
+		for _, b := range s.keys {
+			keyW = max(keyW, lipgloss.Width(b.Help().Key))
+		}
+	}
+
+	lines := []string{}
+	for i, s := range sections {
+		if i != 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, styles.S_TEXT_HIGHLIGHT_SECONDARY.Render(s.group))
+		for _, b := range s.keys {
+			if !b.Enabled() {
+				continue
+			}
+			h := b.Help()
+			lines = append(lines, fmt.Sprintf("  %-*s  ", keyW, h.Key)+styles.S_TEXT_DISABLED.Render(h.Desc))
+		}
+	}
+
+	return lines
+}
+
+func (m mainApp) helpSize() (w, h int) {
+	return min(HELP_MAX_WIDTH, m.width-HELP_MARGIN*2), m.height - HELP_MARGIN*2
+}
+
+// Clamps the scroll offset, so that the last line is at the bottom at most
+func (m mainApp) helpOff(off, lineCount int) int {
+	_, h := m.helpSize()
+	return max(0, min(off, lineCount-(h-HELP_CHROME_H)))
+}
+
+func (m *mainApp) updateHelp(msg tea.KeyPressMsg) {
+	_, h := m.helpSize()
+	page := h - HELP_CHROME_H
+
+	switch {
+	case key.Matches(msg, KEY_HELP, KEY_HELP_CLOSE):
+		m.help = helpOverlay{}
+		return
+	case key.Matches(msg, KEY_HELP_UP):
+		m.help.off--
+	case key.Matches(msg, KEY_HELP_DOWN):
+		m.help.off++
+	case key.Matches(msg, KEY_HELP_PAGE_UP):
+		m.help.off -= page
+	case key.Matches(msg, KEY_HELP_PAGE_DOWN):
+		m.help.off += page
+	}
+
+	m.help.off = m.helpOff(m.help.off, len(m.helpLines()))
+}
+
+// Draws the help in the middle of content, which is expected to be m.width x m.height
+func (m mainApp) overlayHelp(content string) string {
+	w, h := m.helpSize()
+	lines := m.helpLines()
+	rowsH := h - HELP_CHROME_H
+
+	off := m.helpOff(m.help.off, len(lines))
+	lines = lines[off:]
+	if len(lines) > rowsH {
+		lines = lines[:rowsH]
+	}
+
+	// Border + padding
+	innerW := w - 2 - 2
+	title := utils.JoinHorizontal2(
+		innerW,
+		styles.S_TEXT_HIGHLIGHT.Render("Keys"),
+		styles.S_TEXT_DISABLED.Render(KEY_HELP_CLOSE.Help().Key+" close · "+KEY_HELP_UP.Help().Key+"/"+KEY_HELP_DOWN.Help().Key+" scroll"),
+	)
+	for i, l := range lines {
+		lines[i] = utils.Overflow(l, innerW)
+	}
+
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(styles.COLOR_MAIN).
+		Padding(0, 1).
+		Width(w).
+		Height(h).
+		Render(title + "\n\n" + strings.Join(lines, "\n"))
+
+	return lipgloss.NewCanvas(m.width, m.height).Compose(
+		lipgloss.NewCompositor(
+			lipgloss.NewLayer(content),
+			lipgloss.NewLayer(box).X((m.width-lipgloss.Width(box))/2).Y((m.height-lipgloss.Height(box))/2).Z(1),
+		),
+	).Render()
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"charm.land/bubbles/v2/key"
+	"github.com/bank_data_tui/utils/keymap"
+)
+
+var (
+	KEY_QUIT             = key.NewBinding(key.WithKeys("ctrl+c"), key.WithHelp("ctrl+c", "quit"))
+	KEY_HELP             = key.NewBinding(key.WithKeys("?", "f1"), key.WithHelp("?/f1", "show/hide keys"))
+	KEY_LOGOUT           = key.NewBinding(key.WithKeys("ctrl+l"), key.WithHelp("ctrl+l", "logout & forget the saved session"))
+	KEY_NEXT_TAB         = key.NewBinding(key.WithKeys("alt+tab"), key.WithHelp("alt+tab", "next tab"))
+	KEY_PREV_TAB         = key.NewBinding(key.WithKeys("alt+shift+tab"), key.WithHelp("alt+shift+tab", "previous tab"))
+	KEY_TAB_DASHBOARD    = key.NewBinding(key.WithKeys("alt+d"), key.WithHelp("alt+d", "dashboard tab"))
+	KEY_TAB_REPORTS      = key.NewBinding(key.WithKeys("alt+r"), key.WithHelp("alt+r", "reports tab"))
+	KEY_TAB_TRANSACTIONS = key.NewBinding(key.WithKeys("alt+t"), key.WithHelp("alt+t", "transactions tab"))
+	KEY_TAB_MAPPINGS     = key.NewBinding(key.WithKeys("alt+m"), key.WithHelp("alt+m", "mappings tab"))
+	KEY_TAB_CATEGORIES   = key.NewBinding(key.WithKeys("alt+c"), key.WithHelp("alt+c", "categories tab"))
+	KEY_TAB_BUDGETS      = key.NewBinding(key.WithKeys("alt+b"), key.WithHelp("alt+b", "budgets tab"))
+	KEY_TAB_UPLOAD       = key.NewBinding(key.WithKeys("alt+u", "alt+n"), key.WithHelp("alt+u", "upload tab"))
+)
+
+func init() {
+	keymap.Register(keymap.GROUP_GLOBAL, map[string]*key.Binding{
+		"quit":             &KEY_QUIT,
+		"help":             &KEY_HELP,
+		"logout":           &KEY_LOGOUT,
+		"next_tab":         &KEY_NEXT_TAB,
+		"prev_tab":         &KEY_PREV_TAB,
+		"tab_dashboard":    &KEY_TAB_DASHBOARD,
+		"tab_reports":      &KEY_TAB_REPORTS,
+		"tab_transactions": &KEY_TAB_TRANSACTIONS,
+		"tab_mappings":     &KEY_TAB_MAPPINGS,
+		"tab_categories":   &KEY_TAB_CATEGORIES,
+		"tab_budgets":      &KEY_TAB_BUDGETS,
+		"tab_upload":       &KEY_TAB_UPLOAD,
+	})
+}
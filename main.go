@@ -1,14 +1,23 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"path/filepath"
+	"time"
 
 	tea "charm.land/bubbletea/v2"
 	"github.com/bank_data_tui/api"
+	"github.com/bank_data_tui/config"
 	"github.com/bank_data_tui/screens/login"
+	"github.com/bank_data_tui/styles"
 	"github.com/bank_data_tui/utils"
+	"github.com/bank_data_tui/utils/keymap"
+	"github.com/bank_data_tui/utils/notify"
 	"github.com/bank_data_tui/utils/repo"
 	"github.com/joho/godotenv"
 )
@@ -17,54 +26,207 @@ type Screen int
 
 const (
 	S_LOGIN Screen = iota
+	S_DASHBOARD
+	S_REPORTS
 	S_TRANS
 	S_MAPPINGS
 	S_CATEGORIES
+	S_BUDGETS
 	S_UPLOAD
 )
 
+// Names used for screens in the config
+var SCREEN_NAMES = map[string]Screen{
+	"dashboard":    S_DASHBOARD,
+	"reports":      S_REPORTS,
+	"transactions": S_TRANS,
+	"mappings":     S_MAPPINGS,
+	"categories":   S_CATEGORIES,
+	"budgets":      S_BUDGETS,
+	"upload":       S_UPLOAD,
+}
+
 type mainApp struct {
 	curFocusedScreen Screen
-	screenImp        utils.Screen
+	// Every screen that was opened, kept so that switching back to one resumes it
+	screens map[Screen]utils.Screen
 
 	width  int
 	height int
 
-	cache *repo.Cache
-	api   *api.APIClient
+	// Where logging in & MsgGoToHome lead to
+	homeScreen Screen
+	cfg        *config.Config
+
+	cache  *repo.Cache
+	api    *api.APIClient
+	notify notify.Model
+	help   helpOverlay
+	// Ran once in Init, on top of the screen's own init
+	startupCmd tea.Cmd
+
+	ctx context.Context
+	// Every screen's requests use this, cancelled when the screens are thrown away
+	screenCtx     context.Context
+	cancelScreens context.CancelFunc
+
+	session *sessionStore
+	// Set while logging in again after the session expired. The same user gets their screens back as they were
+	expiredUser  string
+	resumeScreen Screen
 }
 
 func (m mainApp) Init() tea.Cmd {
-	return m.screenImp.Init()
+	return tea.Batch(tagCmd(m.curFocusedScreen, m.cur().Init()), m.startupCmd)
+}
+
+func (m mainApp) cur() utils.Screen {
+	return m.screens[m.curFocusedScreen]
 }
 
 func main() {
-	f, err := os.Create("logs/log.log")
+	os.Exit(run())
+}
+
+// Everything main does, returning the exit code instead of exiting so that what's deferred runs
+func run() int {
+	godotenv.Load()
+
+	cfgPath := flag.String("config", envOr("BANK_TUI_CONFIG", config.Path()), "Config file (env BANK_TUI_CONFIG)")
+	server := flag.String("server", "", "Base URL of the bank data server, overrides the config (env BANK_API_URL)")
+	timeout := flag.Duration("timeout", 0, "Time limit for a single API request, overrides the config (env BANK_API_TIMEOUT)")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), CLI_USAGE, "\nFlags:\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	isCLI := flag.NArg() != 0
+
+	cfg, err := loadConfig(*cfgPath, *server, *timeout)
 	if err != nil {
-		panic(err)
+		fmt.Fprintf(os.Stderr, "Bad config in %s:\n%v\n", *cfgPath, err)
+		return EXIT_USAGE
+	}
+	if err := keymap.Apply(cfg.Keys); err != nil {
+		fmt.Fprintf(os.Stderr, "Bad config in %s:\n%v\n", *cfgPath, err)
+		return EXIT_USAGE
+	}
+
+	f, err := openLog(cfg.Log)
+	if err != nil {
+		if !isCLI {
+			fmt.Fprintln(os.Stderr, "Can't open the log file:", err)
+			return EXIT_FAIL
+		}
+		// Scripts shouldn't fail just because they're ran from elsewhere
+		log.SetOutput(io.Discard)
+	} else {
+		log.SetOutput(f)
+		defer f.Close()
+	}
+
+	client := api.NewClient(api.WithBaseURL(cfg.Server), api.WithTimeout(cfg.Timeout))
+	if isCLI {
+		return runCLI(context.Background(), client, openSessionStore(cfg), flag.Args())
 	}
-	log.SetOutput(f)
-	defer f.Close()
-	godotenv.Load()
 
 	app := &mainApp{
 		curFocusedScreen: S_LOGIN,
-		screenImp:        login.NewScreenLogin(),
-		api:              &api.APIClient{},
+		screens:          map[Screen]utils.Screen{S_LOGIN: login.NewScreenLogin()},
+		homeScreen:       SCREEN_NAMES[cfg.DefaultScreen],
+		cfg:              cfg,
+		api:              client,
 		cache:            &repo.Cache{},
+		ctx:              context.Background(),
+		session:          openSessionStore(cfg),
 	}
-	user, pass := os.Getenv("USERNAME"), os.Getenv("PASSWORD")
 
-	if user != "" && pass != "" {
-		err := app.api.Login([2]string{user, pass})
+	app.screenCtx, app.cancelScreens = context.WithCancel(app.ctx)
+
+	restored, err := app.session.restore(app.ctx, app.api)
+	if err != nil {
+		log.Println("Can't restore the session:", err)
+		app.startupCmd = notify.ErrorCmd(err, nil)
+	}
+	if restored {
+		app.switchToScreen(app.homeScreen)
+	} else if creds, ok := envCredentials(); ok {
+		err := app.api.Login(app.ctx, creds)
 		if err != nil {
-			panic(err)
+			log.Println("Can't login from env:", err)
+			app.startupCmd = tea.Batch(app.startupCmd, notify.ErrorCmd(err, nil))
+		} else {
+			app.startupCmd = tea.Batch(app.startupCmd, app.session.saveCmd(app.api.Token(), creds))
+			app.switchToScreen(app.homeScreen)
 		}
-		app.switchToScreen(S_TRANS)
 	}
 
 	p := tea.NewProgram(app)
-	if _, err := p.Run(); err != nil {
+	_, err = p.Run()
+	app.session.close(app.api)
+	if err != nil {
 		fmt.Println(err)
+		return EXIT_FAIL
 	}
+
+	return EXIT_OK
+}
+
+// The config file, with the env & then flags on top of it
+func loadConfig(p string, server string, timeout time.Duration) (*config.Config, error) {
+	cfg, err := config.Load(p)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.Server = envOr("BANK_API_URL", cfg.Server)
+	cfg.Timeout = envDurationOr("BANK_API_TIMEOUT", cfg.Timeout)
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "server":
+			cfg.Server = server
+		case "timeout":
+			cfg.Timeout = timeout
+		}
+	})
+
+	return cfg, styles.SetTheme(cfg.Theme)
+}
+
+func openLog(p string) (*os.File, error) {
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return nil, err
+	}
+
+	return os.Create(p)
+}
+
+// USERNAME & PASSWORD from the env (or .env), if both are set
+func envCredentials() ([2]string, bool) {
+	user, pass := os.Getenv("USERNAME"), os.Getenv("PASSWORD")
+
+	return [2]string{user, pass}, user != "" && pass != ""
+}
+
+func envOr(name, def string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+
+	return def
+}
+
+func envDurationOr(name string, def time.Duration) time.Duration {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		log.Printf("Bad duration in %s, using default: %v", name, err)
+		return def
+	}
+
+	return d
 }
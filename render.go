@@ -9,11 +9,19 @@ import (
 )
 
 var (
-	STYLE_HEADER_TEXT     = lipgloss.NewStyle().Foreground(styles.COLOR_MAIN).Margin(1)
-	STYLE_HEADER_SELECTED = STYLE_HEADER_TEXT.Bold(true).Underline(true)
-	STYLE_HEADER          = lipgloss.NewStyle().Border(lipgloss.DoubleBorder(), false, false, true, false).Margin(0, 0, 1, 0).BorderForeground(styles.COLOR_MAIN)
+	STYLE_HEADER_TEXT     lipgloss.Style
+	STYLE_HEADER_SELECTED lipgloss.Style
+	STYLE_HEADER          lipgloss.Style
 )
 
+func init() {
+	styles.OnTheme(func() {
+		STYLE_HEADER_TEXT = lipgloss.NewStyle().Foreground(styles.COLOR_MAIN).Margin(1)
+		STYLE_HEADER_SELECTED = STYLE_HEADER_TEXT.Bold(true).Underline(true)
+		STYLE_HEADER = lipgloss.NewStyle().Border(lipgloss.DoubleBorder(), false, false, true, false).Margin(0, 0, 1, 0).BorderForeground(styles.COLOR_MAIN)
+	})
+}
+
 const (
 	// 1 (margin bottom) + 1 * 2 (padding top & bot) + 1 line of border + 1 line of text
 	HEADER_HEIGHT = 1 + 1*2 + 1 + 1
@@ -22,27 +30,43 @@ const (
 var HEADER_SCREENS = []struct {
 	s Screen
 	t string
+	// Used instead of t when the full names don't fit
+	short string
 }{
-	{S_TRANS, "Transactions"},
-	{S_MAPPINGS, "Mappings"},
-	{S_CATEGORIES, "Categories"},
-	{S_UPLOAD, "Upload"},
+	{S_DASHBOARD, "Dashboard", "Dash"},
+	{S_REPORTS, "Reports", "Rep"},
+	{S_TRANS, "Transactions", "Trans"},
+	{S_MAPPINGS, "Mappings", "Maps"},
+	{S_CATEGORIES, "Categories", "Cats"},
+	{S_BUDGETS, "Budgets", "Budg"},
+	{S_UPLOAD, "Upload", "Upload"},
 }
 
-func (m mainApp) renderHeader() string {
+func (m mainApp) renderTabs(short bool) (string, string) {
 	r := []string{}
 	for _, h := range HEADER_SCREENS {
+		t := h.t
+		if short {
+			t = h.short
+		}
+
 		if h.s == m.curFocusedScreen {
-			r = append(r, STYLE_HEADER_SELECTED.Render(h.t))
+			r = append(r, STYLE_HEADER_SELECTED.Render(t))
 		} else {
-			r = append(r, STYLE_HEADER_TEXT.Render(h.t))
+			r = append(r, STYLE_HEADER_TEXT.Render(t))
 		}
 	}
 
-	left := r[0]
-	right := lipgloss.JoinHorizontal(lipgloss.Top, r[1:]...)
+	return r[0], lipgloss.JoinHorizontal(lipgloss.Top, r[1:]...)
+}
+
+func (m mainApp) renderHeader() string {
+	left, right := m.renderTabs(false)
+	if lipgloss.Width(left)+lipgloss.Width(right) > m.width {
+		left, right = m.renderTabs(true)
+	}
 
-	spacer := strings.Repeat(" ", m.width-lipgloss.Width(right)-lipgloss.Width(left))
+	spacer := strings.Repeat(" ", max(m.width-lipgloss.Width(right)-lipgloss.Width(left), 0))
 
 	return STYLE_HEADER.Render(lipgloss.JoinHorizontal(lipgloss.Top, left, spacer, right))
 }
@@ -64,7 +88,7 @@ func (m mainApp) View() (v tea.View) {
 		return
 	}
 
-	s, c := m.screenImp.View()
+	s, c := m.cur().View()
 	v.Cursor = c
 
 	w, h := lipgloss.Width(s), lipgloss.Height(s)
@@ -83,7 +107,7 @@ func (m mainApp) View() (v tea.View) {
 			c.X += padLeft
 		}
 
-		v.SetContent(lipgloss.NewStyle().Padding(padTop, 0, 0, padLeft).Render(s))
+		v.SetContent(m.overlay(lipgloss.NewStyle().Padding(padTop, 0, 0, padLeft).Render(s), &v))
 		return
 	}
 
@@ -96,7 +120,17 @@ func (m mainApp) View() (v tea.View) {
 		c.X += padLeft
 	}
 
-	v.SetContent(header + "\n" + lipgloss.NewStyle().Padding(padTop, 0, 0, padLeft).Render(s))
+	v.SetContent(m.overlay(header+"\n"+lipgloss.NewStyle().Padding(padTop, 0, 0, padLeft).Render(s), &v))
 
 	return v
 }
+
+// Draws the help & toasts on top of the screen
+func (m mainApp) overlay(content string, v *tea.View) string {
+	if m.help.open {
+		v.Cursor = nil
+		content = m.overlayHelp(content)
+	}
+
+	return m.notify.Overlay(content, m.width, m.height)
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/bank_data_tui/api/fake"
+	"github.com/bank_data_tui/config"
+	"github.com/bank_data_tui/screens/budgets"
+	"github.com/bank_data_tui/screens/dashboard"
+	"github.com/bank_data_tui/screens/login"
+	"github.com/bank_data_tui/screens/reports"
+	"github.com/bank_data_tui/utils"
+	"github.com/bank_data_tui/utils/repo"
+	"github.com/bank_data_tui/utils/screentest"
+)
+
+// The screens' sizes in screentest.SIZES, with the header on top
+var SIZES = [][2]int{{50, 15 + HEADER_HEIGHT}, {80, 19 + HEADER_HEIGHT}, {120, 35 + HEADER_HEIGHT}}
+
+func init() {
+	dashboard.NOW = screentest.Now
+	reports.NOW = dashboard.NOW
+	budgets.NOW = dashboard.NOW
+}
+
+// mainApp as a screentest.Model
+type testApp struct{ *mainApp }
+
+func (a testApp) Update(msg tea.Msg) (testApp, tea.Cmd) {
+	_, cmd := a.mainApp.Update(msg)
+	return a, cmd
+}
+
+func (a testApp) View() (string, *tea.Cursor) {
+	v := a.mainApp.View()
+	return v.Content, v.Cursor
+}
+
+// Set up like main does, on the login screen of a seeded fake server
+func newHarness(t *testing.T, w, h int) (*screentest.Harness[testApp], *fake.Server) {
+	// The upload tab starts in the working dir, so it gets the filepicker's fixtures
+	home, err := filepath.Abs(filepath.Join("utils", "filepicker", "testdata", "home"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("HOME", home)
+	t.Chdir(filepath.Join(home, "project"))
+
+	c, srv := screentest.Backend(t)
+	c.Logout()
+
+	app := &mainApp{
+		curFocusedScreen: S_LOGIN,
+		screens:          map[Screen]utils.Screen{S_LOGIN: login.NewScreenLogin()},
+		homeScreen:       S_TRANS,
+		cfg:              config.Default(),
+		api:              c,
+		cache:            &repo.Cache{},
+		ctx:              context.Background(),
+		session:          &sessionStore{},
+	}
+	app.screenCtx, app.cancelScreens = context.WithCancel(app.ctx)
+	t.Cleanup(app.cancelScreens)
+
+	sh := screentest.New(t, testApp{app}, app.Init())
+	sh.Unwrap = func(msg tea.Msg) tea.Msg {
+		if msg, ok := msg.(screenMsg); ok {
+			return msg.msg
+		}
+		return msg
+	}
+
+	return sh.Send(tea.WindowSizeMsg{Width: w, Height: h}).Settle(), srv
+}
+
+func logIn(sh *screentest.Harness[testApp]) {
+	sh.Send(login.LoginEntered{"demo", "demo-password"}).Settle()
+}
+
+func TestViewLogin(t *testing.T) {
+	screentest.Sizes(t, SIZES, func(t *testing.T, w, h int) {
+		sh, _ := newHarness(t, w, h)
+		sh.Golden(t.Name())
+
+		sh.Send(login.LoginEntered{"demo", "nope"}).Settle()
+		// Only the colours change
+		sh.GoldenANSI(t.Name() + "_wrong")
+	})
+}
+
+func TestViewTooSmall(t *testing.T) {
+	sh, _ := newHarness(t, 49, 30)
+	sh.Golden(t.Name())
+
+	sh.Send(tea.WindowSizeMsg{Width: 80, Height: 19}).Settle()
+	sh.Golden(t.Name() + "_short")
+}
+
+func TestViewTabs(t *testing.T) {
+	screentest.Sizes(t, SIZES, func(t *testing.T, w, h int) {
+		sh, _ := newHarness(t, w, h)
+		logIn(sh)
+		sh.Golden(t.Name() + "_transactions")
+
+		sh.Keys("alt+m").Settle()
+		sh.Golden(t.Name() + "_mappings")
+
+		sh.Keys("alt+c").Settle()
+		sh.Golden(t.Name() + "_categories")
+
+		sh.Keys("alt+b").Settle()
+		sh.Golden(t.Name() + "_budgets")
+
+		sh.Keys("alt+u").Settle()
+		sh.Golden(t.Name() + "_upload")
+
+		sh.Keys("alt+d").Settle()
+		sh.Golden(t.Name() + "_dashboard")
+
+		sh.Keys("alt+r").Settle()
+		sh.Golden(t.Name() + "_reports")
+	})
+}
+
+func TestViewHelp(t *testing.T) {
+	screentest.Sizes(t, SIZES, func(t *testing.T, w, h int) {
+		sh, _ := newHarness(t, w, h)
+		logIn(sh)
+
+		sh.Keys("?")
+		sh.Golden(t.Name())
+	})
+}
+
+// The password changed while logged in, so the next request can't log back in by itself
+func TestViewSessionExpired(t *testing.T) {
+	screentest.Sizes(t, SIZES, func(t *testing.T, w, h int) {
+		sh, srv := newHarness(t, w, h)
+		logIn(sh)
+
+		srv.AddUser("demo", "changed")
+		srv.RevokeTokens()
+		sh.Keys("alt+m").Settle()
+		sh.Golden(t.Name())
+
+		sh.Send(login.LoginEntered{"demo", "changed"}).Settle()
+		sh.Golden(t.Name() + "_resumed")
+	})
+}
+
+func TestViewMappingAnalysis(t *testing.T) {
+	screentest.Sizes(t, SIZES, func(t *testing.T, w, h int) {
+		sh, _ := newHarness(t, w, h)
+		logIn(sh)
+
+		sh.Keys("alt+m").Settle()
+		sh.Keys("alt+a").Settle()
+		sh.Golden(t.Name())
+
+		// To the last unmatched transaction, then a new mapping for it
+		for range 100 {
+			sh.Keys("down")
+		}
+		sh.Settle()
+		sh.Golden(t.Name() + "_unmatched")
+
+		sh.Keys("enter").Settle()
+		sh.Golden(t.Name() + "_new")
+	})
+}
+
+// Moving a mapping keeps what's typed into its editor
+func TestViewMappingMove(t *testing.T) {
+	screentest.Sizes(t, SIZES, func(t *testing.T, w, h int) {
+		sh, _ := newHarness(t, w, h)
+		logIn(sh)
+
+		sh.Keys("alt+m").Settle()
+		sh.Keys("!", "alt+shift+down").Settle()
+		sh.Golden(t.Name())
+	})
+}
@@ -0,0 +1,121 @@
+package budgets
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"charm.land/bubbles/v2/textinput"
+	"github.com/bank_data_tui/api"
+	"github.com/bank_data_tui/utils/editor"
+	"github.com/bank_data_tui/utils/listeditor"
+)
+
+type budgetProxy struct {
+	api.Budget
+
+	// Name of the budget's category, for filtering. Kept up to date by the editor & refetches
+	catName string
+}
+
+func (b budgetProxy) FilterValue() string {
+	return b.catName
+}
+func (b budgetProxy) GetID() string {
+	return b.ID
+}
+func (b *budgetProxy) SetID(id string) {
+	b.ID = id
+}
+
+func (c *budgetImpl) NewEditor(ctx context.Context, w, h int, v *budgetProxy) *editor.Model {
+	return editor.New(
+		w-listeditor.WIDTH_OFFSET_EDITOR,
+		v.ID,
+		[]*editor.DataField{
+			{
+				Title: "Category",
+				ID:    "category",
+				GetValue: func() string {
+					if cat := c.cache.Category(v.CategoryID); cat != nil {
+						return cat.Name
+					}
+					return ""
+				},
+				SetValue: func(raw string) {
+					v.CategoryID, v.catName = "", ""
+					if cat := c.categoryByName(raw); cat != nil {
+						v.CategoryID, v.catName = cat.ID, cat.Name
+					}
+				},
+				Row:  0,
+				Flex: true,
+			},
+			{
+				Title: "Period",
+				ID:    "period",
+				Value: &v.Period,
+				Row:   1,
+			},
+			{
+				Title: "Limit",
+				ID:    "limit",
+				GetValue: func() string {
+					if v.Limit == 0 {
+						return ""
+					}
+					return strconv.FormatFloat(v.Limit, 'f', -1, 64)
+				},
+				SetValue: func(raw string) {
+					parsed, _ := strconv.ParseFloat(raw, 64)
+					// Validation handles err handling
+					v.Limit = parsed
+				},
+				Row: 1,
+				Col: 1,
+			},
+		},
+		func(_ bool) (string, error) {
+			id, err := c.api.BudgetsCreate(ctx, &v.SavableBudget)
+			if err != nil {
+				return "", err
+			}
+			return id, nil
+		},
+		func(_ bool, id string) error { return c.api.BudgetsUpdate(ctx, id, &v.SavableBudget) },
+		func(_ bool, id string) error { return c.api.BudgetsDelete(ctx, id) },
+		editor.RequireFields(0, 1, 2),
+		editor.AddFieldValidator(0, func(s string) error {
+			if c.categoryByName(s) == nil {
+				return fmt.Errorf("Must be a valid category")
+			}
+
+			return nil
+		}),
+		editor.AddFieldValidator(1, func(s string) error {
+			if !slices.Contains(api.BUDGET_PERIODS, s) {
+				return fmt.Errorf("Must be %s", strings.Join(api.BUDGET_PERIODS, " or "))
+			}
+
+			return nil
+		}),
+		editor.AddFloatValidator(2),
+		editor.AddFieldValidator(2, func(s string) error {
+			if f, err := strconv.ParseFloat(s, 64); err == nil && f <= 0 {
+				return fmt.Errorf("Must be above 0")
+			}
+
+			return nil
+		}),
+		func(fields []*textinput.Model) {
+			fields[0].ShowSuggestions = true
+			c.categoryField = fields[0]
+			c.resetSuggestions()
+
+			fields[1].ShowSuggestions = true
+			fields[1].SetSuggestions(api.BUDGET_PERIODS)
+		},
+	)
+}
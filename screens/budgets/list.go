@@ -0,0 +1,57 @@
+package budgets
+
+import (
+	"io"
+	"strconv"
+
+	"charm.land/bubbles/v2/list"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/bank_data_tui/api"
+	"github.com/bank_data_tui/styles"
+	"github.com/bank_data_tui/utils"
+	"github.com/bank_data_tui/utils/chart"
+	"github.com/bank_data_tui/utils/listeditor"
+)
+
+type budgetDelegate struct {
+	impl *budgetImpl
+}
+
+func (budgetDelegate) Spacing() int { return 1 }
+func (budgetDelegate) Height() int  { return 1 }
+
+func (d budgetDelegate) Render(w io.Writer, m list.Model, i int, v list.Item) {
+	style := lipgloss.NewStyle().Foreground(styles.COLOR_MAIN)
+	if m.GlobalIndex() == i {
+		style = style.Underline(true)
+	}
+
+	txt, ok := v.(listeditor.NewItem)
+	if ok {
+		w.Write(
+			[]byte(" " + style.Render(string(txt))),
+		)
+
+		return
+	}
+
+	val := v.(*budgetProxy)
+	name := val.catName
+	if cat := d.impl.cache.Category(val.CategoryID); cat != nil {
+		name = cat.Icon + " " + cat.Name
+		style = chart.CategoryStyle(cat).Underline(m.GlobalIndex() == i)
+	}
+
+	limit := strconv.FormatFloat(val.Limit, 'f', -1, 64) + "/mo"
+	if val.Period == api.BUDGET_WEEKLY {
+		limit = strconv.FormatFloat(val.Limit, 'f', -1, 64) + "/wk"
+	}
+	nameW := listeditor.WIDTH_LIST - 1 - lipgloss.Width(limit) - 1
+
+	w.Write([]byte(" " + style.Render(
+		lipgloss.NewStyle().Width(nameW).Render(utils.Overflow(name, nameW)),
+	) + " " + styles.S_TEXT_DISABLED.Render(limit)))
+}
+
+func (budgetDelegate) Update(msg tea.Msg, m *list.Model) tea.Cmd { return nil }
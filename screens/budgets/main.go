@@ -0,0 +1,152 @@
+package budgets
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"charm.land/bubbles/v2/textinput"
+	tea "charm.land/bubbletea/v2"
+	"github.com/bank_data_tui/api"
+	"github.com/bank_data_tui/config"
+	"github.com/bank_data_tui/styles"
+	"github.com/bank_data_tui/utils"
+	"github.com/bank_data_tui/utils/editor"
+	"github.com/bank_data_tui/utils/listeditor"
+	"github.com/bank_data_tui/utils/notify"
+	"github.com/bank_data_tui/utils/repo"
+)
+
+type budgetImpl struct {
+	cfg           *config.Config
+	cache         *repo.Cache
+	api           *api.APIClient
+	categoryField *textinput.Model
+
+	// Same items as the list editor, for the selected one's progress
+	budgets []*budgetProxy
+	// nil until loaded
+	spending *Spending
+}
+
+func (m *budgetImpl) InitialFetch(ctx context.Context) ([]*budgetProxy, error) {
+	if _, err := m.cache.EasyCategories(ctx, m.api); err != nil {
+		return nil, err
+	}
+
+	all, err := m.api.BudgetsFetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	m.cache.Budgets = all
+
+	arr := make([]*budgetProxy, len(all))
+	for i, v := range all {
+		arr[i] = &budgetProxy{Budget: *v}
+		if cat := m.cache.Category(v.CategoryID); cat != nil {
+			arr[i].catName = cat.Name
+		}
+	}
+
+	return arr, nil
+}
+
+type spendingLoaded Spending
+
+func (m *budgetImpl) Init(ctx context.Context) tea.Cmd {
+	return m.fetchSpending(ctx)
+}
+
+func (m *budgetImpl) fetchSpending(ctx context.Context) tea.Cmd {
+	return func() tea.Msg {
+		s, err := FetchSpending(ctx, m.api, m.cfg)
+		if err != nil {
+			return notify.Error(err, m.fetchSpending(ctx))
+		}
+
+		return spendingLoaded(s)
+	}
+}
+
+func (m *budgetImpl) Update(msg tea.Msg) {
+	switch msg := msg.(type) {
+	case listeditor.ItemsLoaded:
+		m.budgets = slices.Clone(msg.Items.([]*budgetProxy))
+		// Categories are loaded along with the budgets
+		m.resetSuggestions()
+	case spendingLoaded:
+		s := Spending(msg)
+		m.spending = &s
+	case editor.ItemDel:
+		m.budgets = slices.DeleteFunc(m.budgets, func(v *budgetProxy) bool { return v.ID == string(msg) })
+	case listeditor.ItemNew:
+		m.budgets = append(m.budgets, msg.Value.(*budgetProxy))
+	}
+
+	switch msg.(type) {
+	case editor.ItemDel, listeditor.ItemNew, listeditor.ItemUpdate:
+		// The categories screen shows these too
+		m.cache.Budgets = nil
+	}
+}
+
+// Ordered like the categories are
+func (m *budgetImpl) Compare(a, b *budgetProxy) int {
+	return strings.Compare(strings.ToLower(a.catName), strings.ToLower(b.catName))
+}
+
+func (m *budgetImpl) categoryByName(name string) *api.Category {
+	for _, c := range m.cache.Categories {
+		if strings.EqualFold(name, c.Name) {
+			return c
+		}
+	}
+
+	return nil
+}
+
+func (m *budgetImpl) resetSuggestions() {
+	sl := make([]string, len(m.cache.Categories))
+	for i, v := range m.cache.Categories {
+		sl[i] = v.Name
+	}
+
+	m.categoryField.SetSuggestions(sl)
+}
+
+// How the selected budget is doing, against the spending as of the last fetch. Only for saved budgets
+func (m *budgetImpl) EditorFooter(w, h int, e *editor.Model) string {
+	i := slices.IndexFunc(m.budgets, func(v *budgetProxy) bool { return v.ID == e.ItemID })
+	if e.ItemID == "" || i == -1 || m.spending == nil {
+		return ""
+	}
+	p := m.spending.Progress(&m.budgets[i].Budget)
+
+	spent := "Spent " + m.cfg.Amount.Format(p.Spent) + " of " + m.cfg.Amount.Format(p.Budget.Limit) + " " + periodName(p.Budget)
+	left := styles.S_TEXT_DISABLED.Render(m.cfg.Amount.Format(p.Budget.Limit-p.Spent) + " left")
+	if p.Over() {
+		left = styles.S_TEXT_WRONG.Render(m.cfg.Amount.Format(p.Spent-p.Budget.Limit) + " over")
+	}
+
+	lines := []string{
+		utils.Overflow(spent, w),
+		RenderProgress(w, p, m.cache.Category(p.Budget.CategoryID)),
+		utils.Overflow(left, w),
+	}
+
+	return strings.Join(lines[:min(len(lines), h)], "\n")
+}
+
+func New(ctx context.Context, c *api.APIClient, cache *repo.Cache, cfg *config.Config, w, h int) *listeditor.Model[budgetProxy, *budgetProxy] {
+	impl := &budgetImpl{
+		cfg:   cfg,
+		api:   c,
+		cache: cache,
+	}
+	m := listeditor.New[budgetProxy](
+		ctx, "New Budget", budgetDelegate{impl: impl}, w, h,
+	)
+	m.Abstraction = impl
+
+	return m
+}
@@ -0,0 +1,142 @@
+package budgets
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"charm.land/lipgloss/v2"
+	"github.com/bank_data_tui/api"
+	"github.com/bank_data_tui/config"
+	"github.com/bank_data_tui/styles"
+	"github.com/bank_data_tui/utils/chart"
+	"github.com/bank_data_tui/utils/repo"
+)
+
+// What the current periods are worked out from. Swapped out in tests
+var NOW = time.Now
+
+// What's been spent against a budget in its current period, as a positive number
+type Progress struct {
+	Budget *api.Budget
+	Spent  float64
+}
+
+// Share of the limit that's spent, above 1 once it's gone over
+func (p Progress) Used() float64 {
+	if p.Budget.Limit <= 0 {
+		return 0
+	}
+
+	return p.Spent / p.Budget.Limit
+}
+
+func (p Progress) Over() bool {
+	return p.Spent > p.Budget.Limit
+}
+
+// Spent so far this month & this week, by category id
+type Spending struct {
+	Month, Week map[string]float64
+}
+
+func (s Spending) Progress(b *api.Budget) Progress {
+	spent := s.Month
+	if b.Period == api.BUDGET_WEEKLY {
+		spent = s.Week
+	}
+
+	return Progress{Budget: b, Spent: spent[b.CategoryID]}
+}
+
+// For whatever the budgets are, so that they can be changed without a refetch. Pages are as big as cfg has them
+func FetchSpending(ctx context.Context, c *api.APIClient, cfg *config.Config) (Spending, error) {
+	now := NOW()
+	month := (&api.SavableBudget{Period: api.BUDGET_MONTHLY}).PeriodStart(now)
+	week := (&api.SavableBudget{Period: api.BUDGET_WEEKLY}).PeriodStart(now)
+	from := month
+	if week.Before(from) {
+		from = week
+	}
+
+	// Only spending is counted
+	amtMax := 0.0
+	all := []*api.Transaction{}
+	for page := 1; ; page++ {
+		d, err := c.TransactionsFetch(ctx, api.TransactionQuery{
+			Page:      page,
+			PageSize:  cfg.Transactions.PageSize,
+			From:      from,
+			AmountMax: &amtMax,
+		})
+		if err != nil {
+			return Spending{}, err
+		}
+
+		all = append(all, d.Data...)
+		if len(d.Data) == 0 || len(all) >= d.Total {
+			break
+		}
+	}
+
+	s := Spending{Month: map[string]float64{}, Week: map[string]float64{}}
+	for _, t := range all {
+		if t.ResolvedCategoryID == nil || t.Amount >= 0 || t.AuthedAt.After(now) {
+			continue
+		}
+
+		if !t.AuthedAt.Before(month) {
+			s.Month[*t.ResolvedCategoryID] -= t.Amount
+		}
+		if !t.AuthedAt.Before(week) {
+			s.Week[*t.ResolvedCategoryID] -= t.Amount
+		}
+	}
+
+	return s, nil
+}
+
+// Every budget's progress, by category id
+func FetchProgress(ctx context.Context, c *api.APIClient, cache *repo.Cache, cfg *config.Config) (map[string]Progress, error) {
+	bs, err := cache.EasyBudgets(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	res := map[string]Progress{}
+	if len(bs) == 0 {
+		return res, nil
+	}
+
+	s, err := FetchSpending(ctx, c, cfg)
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range bs {
+		res[b.CategoryID] = s.Progress(b)
+	}
+
+	return res, nil
+}
+
+// A w wide bar of how much of the budget is spent, then the %. In cat's colour, or COLOR_WRONG once it's over
+func RenderProgress(w int, p Progress, cat *api.Category) string {
+	pct := fmt.Sprintf("%3.0f%%", p.Used()*100)
+
+	color, pctStyle := chart.CategoryColor(cat), styles.S_TEXT_DISABLED
+	if p.Over() {
+		color, pctStyle = styles.COLOR_WRONG, styles.S_TEXT_WRONG
+	}
+
+	barW := max(w-lipgloss.Width(pct)-1, 0)
+	return chart.Progress(barW, p.Spent, p.Budget.Limit, color) + " " + pctStyle.Render(pct)
+}
+
+// "this month" or "this week"
+func periodName(b *api.Budget) string {
+	if b.Period == api.BUDGET_WEEKLY {
+		return "this week"
+	}
+
+	return "this month"
+}
@@ -0,0 +1,107 @@
+package budgets
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bank_data_tui/api"
+	"github.com/bank_data_tui/api/fake"
+	"github.com/bank_data_tui/config"
+	"github.com/bank_data_tui/utils/screentest"
+)
+
+func TestFetchSpending(t *testing.T) {
+	t.Cleanup(func() { NOW = screentest.Now })
+	at := func(m time.Month, d, h, min int) time.Time { return time.Date(2024, m, d, h, min, 0, 0, time.UTC) }
+
+	type tx struct {
+		at     time.Time
+		amount float64
+		// Only SHOP is in the category
+		desc string
+	}
+	for _, c := range []struct {
+		name string
+		// 2024-03-06 is a Wednesday, 2024-03-01 a Friday
+		now         time.Time
+		ts          []tx
+		month, week float64
+	}{
+		{
+			name: "week inside the month",
+			now:  at(3, 6, 12, 0),
+			ts: []tx{
+				{at(2, 29, 23, 59), -1, "SHOP"},
+				{at(3, 1, 0, 0), -2, "SHOP"},
+				{at(3, 3, 23, 59), -4, "SHOP"},
+				{at(3, 4, 0, 0), -8, "SHOP"},
+				// Not yet
+				{at(3, 6, 13, 0), -16, "SHOP"},
+				// Not spending
+				{at(3, 5, 0, 0), 32, "SHOP"},
+				// Not in the category
+				{at(3, 5, 0, 0), -64, "ELSEWHERE"},
+			},
+			month: 14, week: 8,
+		},
+		{
+			name: "week from the month before",
+			now:  at(3, 1, 12, 0),
+			ts: []tx{
+				{at(2, 25, 23, 59), -1, "SHOP"},
+				{at(2, 26, 0, 0), -2, "SHOP"},
+				{at(2, 29, 23, 59), -4, "SHOP"},
+				{at(3, 1, 0, 0), -8, "SHOP"},
+			},
+			month: 8, week: 14,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			s := fake.New()
+			s.AddUser("demo", "demo-password")
+			cat := s.AddCategory(api.SavableCategory{Name: "Shopping"})
+			s.AddMapping(api.Mapping{Name: "Shop", InpText: "^SHOP", ResCategoryID: cat, Priority: 1})
+			for _, v := range c.ts {
+				s.AddTransaction(api.Transaction{AuthedAt: v.at, SettledAt: v.at, Desc: v.desc, Amount: v.amount})
+			}
+
+			pageSizes := []string{}
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/transactions" {
+					pageSizes = append(pageSizes, r.URL.Query().Get("page_size"))
+				}
+				s.ServeHTTP(w, r)
+			}))
+			t.Cleanup(srv.Close)
+
+			client := api.NewClient(api.WithBaseURL(srv.URL))
+			if err := client.Login(context.Background(), [2]string{"demo", "demo-password"}); err != nil {
+				t.Fatal(err)
+			}
+
+			cfg := config.Default()
+			cfg.Transactions.PageSize = 2
+			NOW = func() time.Time { return c.now }
+
+			got, err := FetchSpending(context.Background(), client, cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Month[cat] != c.month || got.Week[cat] != c.week {
+				t.Errorf("month %v, week %v, want %v, %v", got.Month[cat], got.Week[cat], c.month, c.week)
+			}
+
+			if len(pageSizes) < 2 {
+				t.Errorf("fetched %d pages, want them in pages of 2", len(pageSizes))
+			}
+			for _, v := range pageSizes {
+				if v != "2" {
+					t.Errorf("page_size = %q, want the config's 2", v)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package budgets
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bank_data_tui/api"
+	"github.com/bank_data_tui/config"
+	"github.com/bank_data_tui/utils"
+	"github.com/bank_data_tui/utils/repo"
+	"github.com/bank_data_tui/utils/screentest"
+)
+
+func init() {
+	NOW = screentest.Now
+}
+
+func newScreen(c *api.APIClient, w, h int) utils.Screen {
+	return New(context.Background(), c, &repo.Cache{}, config.Default(), w, h)
+}
+
+func TestView(t *testing.T) {
+	screentest.Sizes(t, screentest.SIZES, func(t *testing.T, w, h int) {
+		sh := screentest.ScreenHarness(t, w, h, newScreen)
+		sh.Golden(t.Name())
+
+		// Transport, which has gone over
+		sh.Keys("alt+down", "alt+down").Settle()
+		sh.Golden(t.Name() + "_over")
+		sh.GoldenANSI(t.Name() + "_over")
+
+		sh.Keys("alt+down").Settle()
+		sh.Golden(t.Name() + "_new")
+	})
+}
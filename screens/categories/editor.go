@@ -1,6 +1,7 @@
 package categories
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 
@@ -35,7 +36,7 @@ func verifyColor(s string) error {
 	return nil
 }
 
-func (c *categoryImpl) NewEditor(w, h int, v *categoryProxy) *editor.Model {
+func (c *categoryImpl) NewEditor(ctx context.Context, w, h int, v *categoryProxy) *editor.Model {
 	return editor.New(
 		w-listeditor.WIDTH_OFFSET_EDITOR,
 		v.ID,
@@ -67,14 +68,14 @@ func (c *categoryImpl) NewEditor(w, h int, v *categoryProxy) *editor.Model {
 			},
 		},
 		func(_ bool) (string, error) {
-			id, err := c.api.CategoriesCreate(&v.SavableCategory)
+			id, err := c.api.CategoriesCreate(ctx, &v.SavableCategory)
 			if err != nil {
 				return "", err
 			}
 			return id, nil
 		},
-		func(_ bool, id string) error { return c.api.CategoriesUpdate(id, &v.SavableCategory) },
-		func(_ bool, id string) error { return c.api.CategoriesDelete(id) },
+		func(_ bool, id string) error { return c.api.CategoriesUpdate(ctx, id, &v.SavableCategory) },
+		func(_ bool, id string) error { return c.api.CategoriesDelete(ctx, id) },
 		editor.RequireFields(0, 1, 2),
 		editor.AddFieldValidator(1, func(s string) error {
 			return verifyColor(s)
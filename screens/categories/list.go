@@ -2,19 +2,28 @@ package categories
 
 import (
 	"io"
+	"strings"
 
 	"charm.land/bubbles/v2/list"
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
+	"github.com/bank_data_tui/api"
+	"github.com/bank_data_tui/screens/budgets"
 	"github.com/bank_data_tui/styles"
 	"github.com/bank_data_tui/utils"
 	"github.com/bank_data_tui/utils/listeditor"
 )
 
-type categoryDelegate struct{}
+// Lines the progress up with the name, past the icon
+const PROGRESS_INDENT = 5
 
-func (c categoryDelegate) Spacing() int { return 1 }
-func (c categoryDelegate) Height() int  { return 1 }
+type categoryDelegate struct {
+	impl *categoryImpl
+}
+
+// The budget's progress goes under the name, so there's no need for spacing
+func (c categoryDelegate) Spacing() int { return 0 }
+func (c categoryDelegate) Height() int  { return 2 }
 
 func (c categoryDelegate) Render(w io.Writer, m list.Model, i int, v list.Item) {
 	style := lipgloss.NewStyle().Foreground(styles.COLOR_MAIN)
@@ -25,7 +34,7 @@ func (c categoryDelegate) Render(w io.Writer, m list.Model, i int, v list.Item)
 	txt, ok := v.(listeditor.NewItem)
 	if ok {
 		w.Write(
-			[]byte(" " + style.Render(string(txt))),
+			[]byte(" " + style.Render(string(txt)) + "\n"),
 		)
 
 		return
@@ -39,6 +48,14 @@ func (c categoryDelegate) Render(w io.Writer, m list.Model, i int, v list.Item)
 	w.Write(
 		[]byte(" " + style.Render(utils.Overflow("["+cat.Icon+"] "+cat.Name, listeditor.WIDTH_LIST - 1))),
 	)
+
+	// Always the second line, even when empty, so that every item is Height tall
+	w.Write([]byte("\n"))
+	if p, ok := c.impl.progress[cat.ID]; ok {
+		w.Write([]byte(strings.Repeat(" ", PROGRESS_INDENT) + budgets.RenderProgress(
+			listeditor.WIDTH_LIST-PROGRESS_INDENT, p, (*api.Category)(cat),
+		)))
+	}
 }
 
 func (c categoryDelegate) Update(msg tea.Msg, m *list.Model) tea.Cmd {
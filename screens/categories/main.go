@@ -1,23 +1,30 @@
 package categories
 
 import (
+	"context"
 	"slices"
 
 	tea "charm.land/bubbletea/v2"
 	"github.com/bank_data_tui/api"
+	"github.com/bank_data_tui/config"
+	"github.com/bank_data_tui/screens/budgets"
 	"github.com/bank_data_tui/utils/editor"
 	"github.com/bank_data_tui/utils/listeditor"
+	"github.com/bank_data_tui/utils/notify"
 	"github.com/bank_data_tui/utils/repo"
 )
 
 type categoryImpl struct {
 	api *api.APIClient
+	cfg *config.Config
 
 	cache *repo.Cache
+	// Of the categories with a budget, by id
+	progress map[string]budgets.Progress
 }
 
-func (m *categoryImpl) InitialFetch() ([]*categoryProxy, error) {
-	c, err := m.cache.EasyCategories(m.api)
+func (m *categoryImpl) InitialFetch(ctx context.Context) ([]*categoryProxy, error) {
+	c, err := m.cache.EasyCategories(ctx, m.api)
 	if err != nil {
 		return nil, err
 	}
@@ -30,9 +37,31 @@ func (m *categoryImpl) InitialFetch() ([]*categoryProxy, error) {
 	return arr, nil
 }
 
+type progressLoaded map[string]budgets.Progress
+
+// Budgets & spending could've changed on any other screen, this is ran again on focus
+func (m *categoryImpl) Init(ctx context.Context) tea.Cmd {
+	return m.fetchProgress(ctx)
+}
+
+func (m *categoryImpl) fetchProgress(ctx context.Context) tea.Cmd {
+	return func() tea.Msg {
+		p, err := budgets.FetchProgress(ctx, m.api, m.cache, m.cfg)
+		if err != nil {
+			return notify.Error(err, m.fetchProgress(ctx))
+		}
+
+		return progressLoaded(p)
+	}
+}
+
 func (m *categoryImpl) Update(msg tea.Msg) {
 	switch msg := msg.(type) {
+	case progressLoaded:
+		m.progress = msg
 	case editor.ItemDel:
+		// Its budget is deleted with it
+		m.cache.Budgets = nil
 		i := slices.IndexFunc(m.cache.Categories, func(c *api.Category) bool {
 			return c.ID == string(msg)
 		})
@@ -54,14 +83,16 @@ func (m *categoryImpl) Update(msg tea.Msg) {
 	}
 }
 
-func New(c *api.APIClient, cache *repo.Cache, w, h int) *listeditor.Model[categoryProxy, *categoryProxy] {
-	m := listeditor.New[categoryProxy](
-		"New Category", categoryDelegate{}, w, h,
-	)
-	m.Abstraction = &categoryImpl{
+func New(ctx context.Context, c *api.APIClient, cache *repo.Cache, cfg *config.Config, w, h int) *listeditor.Model[categoryProxy, *categoryProxy] {
+	impl := &categoryImpl{
 		api:   c,
+		cfg:   cfg,
 		cache: cache,
 	}
+	m := listeditor.New[categoryProxy](
+		ctx, "New Category", categoryDelegate{impl: impl}, w, h,
+	)
+	m.Abstraction = impl
 
 	return m
 }
@@ -0,0 +1,113 @@
+package dashboard
+
+import (
+	"context"
+	"time"
+
+	"charm.land/bubbles/v2/spinner"
+	tea "charm.land/bubbletea/v2"
+	"github.com/bank_data_tui/api"
+	"github.com/bank_data_tui/config"
+	"github.com/bank_data_tui/styles"
+	"github.com/bank_data_tui/utils"
+	"github.com/bank_data_tui/utils/notify"
+	"github.com/bank_data_tui/utils/repo"
+)
+
+// What this month is worked out from. Swapped out in tests
+var NOW = time.Now
+
+type Model struct {
+	ctx   context.Context
+	api   *api.APIClient
+	cache *repo.Cache
+	cfg   *config.Config
+	w, h  int
+
+	spin spinner.Model
+	// nil until the first load. Kept while refreshing, so that it doesn't flash
+	sum *summary
+	// Bumped on every fetch, so that a slow old one doesn't overwrite a newer one
+	gen int
+}
+
+func New(ctx context.Context, api *api.APIClient, cache *repo.Cache, cfg *config.Config, w, h int) *Model {
+	return &Model{
+		ctx:   ctx,
+		api:   api,
+		cache: cache,
+		cfg:   cfg,
+		w:     w,
+		h:     h,
+		spin:  spinner.New(spinner.WithStyle(styles.S_TEXT_HIGHLIGHT)),
+	}
+}
+
+type loaded struct {
+	sum *summary
+	gen int
+}
+
+func (m *Model) Init() tea.Cmd {
+	return tea.Batch(m.spin.Tick, m.refresh())
+}
+
+// Transactions & categories could've changed on any other screen
+func (m *Model) Focus() tea.Cmd {
+	return m.refresh()
+}
+
+func (m *Model) refresh() tea.Cmd {
+	m.gen++
+	return m.fetch(m.gen)
+}
+
+// Everything since the start of last month, & the categories to show them with
+func (m *Model) fetch(gen int) tea.Cmd {
+	return func() tea.Msg {
+		now := NOW()
+		from := monthStart(now).AddDate(0, -1, 0)
+
+		all := []*api.Transaction{}
+		for p := 1; ; p++ {
+			d, err := m.api.TransactionsFetch(m.ctx, api.TransactionQuery{
+				Page:     p,
+				PageSize: m.cfg.Transactions.PageSize,
+				From:     from,
+			})
+			if err != nil {
+				return notify.Error(err, m.fetch(gen))
+			}
+
+			all = append(all, d.Data...)
+			if len(d.Data) == 0 || len(all) >= d.Total {
+				break
+			}
+		}
+
+		if _, err := m.cache.EasyCategories(m.ctx, m.api); err != nil {
+			return notify.Error(err, m.fetch(gen))
+		}
+
+		return loaded{sum: summarise(all, m.cache, now), gen: gen}
+	}
+}
+
+func (m *Model) Update(msg tea.Msg) (utils.Screen, tea.Cmd) {
+	switch msg := msg.(type) {
+	case loaded:
+		if msg.gen == m.gen {
+			m.sum = msg.sum
+		}
+	case utils.ResizeMessage:
+		m.w, m.h = msg.W, msg.H
+	case spinner.TickMsg:
+		if m.sum == nil {
+			var cmd tea.Cmd
+			m.spin, cmd = m.spin.Update(msg)
+			return m, cmd
+		}
+	}
+
+	return m, nil
+}
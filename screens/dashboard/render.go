@@ -0,0 +1,143 @@
+package dashboard
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/bank_data_tui/styles"
+	"github.com/bank_data_tui/utils"
+	"github.com/bank_data_tui/utils/chart"
+)
+
+const (
+	// Overview labels are padded to this
+	LABEL_WIDTH = 22
+	// Below this, the lists go under each other instead of next to each other
+	SIDE_BY_SIDE_WIDTH = 80
+	// Between the lists, when they're next to each other
+	LIST_GAP = 4
+)
+
+var STYLE_HEADING lipgloss.Style
+
+func init() {
+	styles.OnTheme(func() {
+		STYLE_HEADING = lipgloss.NewStyle().Bold(true).Foreground(styles.COLOR_MAIN)
+	})
+}
+
+func (m *Model) View() (string, *tea.Cursor) {
+	if m.sum == nil {
+		return m.spin.View(), nil
+	}
+
+	over := m.renderOverview()
+	// The overview, a blank line & the lists' headings
+	rows := m.h - lipgloss.Height(over) - 2
+
+	var lists string
+	if m.w >= SIDE_BY_SIDE_WIDTH {
+		w := (m.w - LIST_GAP) / 2
+		lists = lipgloss.JoinHorizontal(
+			lipgloss.Top,
+			lipgloss.NewStyle().Width(w+LIST_GAP).Render(m.renderCategories(w, rows)),
+			m.renderLargest(w, rows),
+		)
+	} else {
+		// A blank line & another heading between them
+		rows -= 2
+		lists = m.renderCategories(m.w, rows-rows/2) + "\n\n" + m.renderLargest(m.w, rows/2)
+	}
+
+	box := lipgloss.NewStyle().Width(m.w).Height(m.h).MaxHeight(m.h)
+	return box.Render(over + "\n\n" + lists), nil
+}
+
+func (m *Model) renderOverview() string {
+	s := m.sum
+	label := func(l string) string {
+		return styles.S_TEXT_DISABLED.Render(fmt.Sprintf("%-*s", LABEL_WIDTH, l))
+	}
+
+	lines := []string{
+		label("Spent this month") + m.cfg.Amount.Format(s.thisMonth) + m.renderDelta(),
+		label("Last month by now") + m.cfg.Amount.Format(s.lastMonthSoFar),
+		label("All of last month") + m.cfg.Amount.Format(s.lastMonth),
+		label("Uncategorised") + strconv.Itoa(s.uncategorised) + " this month" + styles.S_TEXT_DISABLED.Render(
+			" ("+m.cfg.Amount.Format(s.uncategorisedSpent)+")",
+		),
+	}
+	for i, l := range lines {
+		lines[i] = utils.Overflow(l, m.w)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// How this month compares to last month so far, spending more is bad
+func (m *Model) renderDelta() string {
+	s := m.sum
+	if s.lastMonthSoFar == 0 {
+		return ""
+	}
+
+	pct := (s.thisMonth - s.lastMonthSoFar) / s.lastMonthSoFar * 100
+	switch {
+	case pct >= 0.5:
+		return styles.S_TEXT_WRONG.Render(fmt.Sprintf("  ▲ %.0f%%", pct))
+	case pct <= -0.5:
+		return styles.S_TEXT_HIGHLIGHT_SECONDARY.Render(fmt.Sprintf("  ▼ %.0f%%", -pct))
+	}
+
+	return styles.S_TEXT_DISABLED.Render("  same")
+}
+
+// left fills whatever right doesn't need of w
+func row(w int, left, right string) string {
+	return utils.JoinHorizontal2(w, utils.Overflow(left, w-lipgloss.Width(right)-1), right)
+}
+
+func (m *Model) renderCategories(w, rows int) string {
+	heading := STYLE_HEADING.Render("Top categories")
+	if len(m.sum.categories) == 0 {
+		return heading + "\n" + styles.S_TEXT_DISABLED.Render("Nothing categorised yet")
+	}
+
+	bars := []chart.Bar{}
+	for _, c := range m.sum.categories[:min(len(m.sum.categories), max(rows, 0))] {
+		bars = append(bars, chart.Bar{
+			Label: chart.CategoryStyle(c.cat).Render(c.cat.Icon + " " + c.cat.Name),
+			Value: c.spent,
+			Color: chart.CategoryColor(c.cat),
+		})
+	}
+
+	return heading + "\n" + chart.Bars(w, bars, func(v float64) string {
+		return m.cfg.Amount.Format(v) + styles.S_TEXT_DISABLED.Render(fmt.Sprintf(" %3.0f%%", v/m.sum.thisMonth*100))
+	})
+}
+
+func (m *Model) renderLargest(w, rows int) string {
+	lines := []string{STYLE_HEADING.Render("Largest this month")}
+	if len(m.sum.largest) == 0 {
+		lines = append(lines, styles.S_TEXT_DISABLED.Render("Nothing spent yet"))
+	}
+
+	for _, t := range m.sum.largest[:min(len(m.sum.largest), max(rows, 0))] {
+		name := t.Desc
+		if t.ResolvedName != nil {
+			name = *t.ResolvedName
+		}
+
+		lines = append(lines, row(
+			w,
+			styles.S_TEXT_DISABLED.Render(m.cfg.FormatDate(t.AuthedAt))+" "+name,
+			m.cfg.Amount.Format(t.Amount),
+		))
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,44 @@
+package dashboard
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bank_data_tui/api"
+	"github.com/bank_data_tui/config"
+	"github.com/bank_data_tui/utils"
+	"github.com/bank_data_tui/utils/repo"
+	"github.com/bank_data_tui/utils/screentest"
+)
+
+func init() {
+	NOW = screentest.Now
+}
+
+func newScreen(c *api.APIClient, w, h int) utils.Screen {
+	return New(context.Background(), c, &repo.Cache{}, config.Default(), w, h)
+}
+
+func TestView(t *testing.T) {
+	screentest.Sizes(t, screentest.SIZES, func(t *testing.T, w, h int) {
+		sh := screentest.ScreenHarness(t, w, h, newScreen)
+		sh.Golden(t.Name())
+	})
+}
+
+// Nothing spent yet this month, & nothing at all the month before
+func TestViewEmpty(t *testing.T) {
+	NOW = func() time.Time { return screentest.SEED_NOW.AddDate(0, 2, 0) }
+	t.Cleanup(func() { NOW = screentest.Now })
+
+	sh := screentest.ScreenHarness(t, 80, 19, newScreen)
+	sh.Golden(t.Name())
+}
+
+func TestViewResize(t *testing.T) {
+	sh := screentest.ScreenHarness(t, 120, 35, newScreen)
+	sh.Send(utils.ResizeMessage{W: 50, H: 15})
+	sh.Golden(t.Name())
+	sh.GoldenANSI(t.Name())
+}
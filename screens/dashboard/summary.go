@@ -0,0 +1,106 @@
+package dashboard
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/bank_data_tui/api"
+	"github.com/bank_data_tui/utils/repo"
+)
+
+// How many of this month's biggest spends are kept
+const LARGEST_COUNT = 10
+
+type categorySpend struct {
+	cat   *api.Category
+	spent float64
+}
+
+// Spending is money going out, as a positive number. Income isn't counted anywhere
+type summary struct {
+	now time.Time
+
+	thisMonth float64
+	// Last month, up to the same point as now is in this month
+	lastMonthSoFar float64
+	lastMonth      float64
+
+	// This month's spending per category, biggest first
+	categories []categorySpend
+	// This month's biggest spends, biggest first
+	largest []*api.Transaction
+	// This month's transactions without a category (or with one that's gone)
+	uncategorised      int
+	uncategorisedSpent float64
+}
+
+func monthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+func summarise(ts []*api.Transaction, cache *repo.Cache, now time.Time) *summary {
+	s := &summary{now: now}
+
+	thisStart := monthStart(now)
+	lastStart := thisStart.AddDate(0, -1, 0)
+	lastSoFar := lastStart.Add(now.Sub(thisStart))
+	if lastSoFar.After(thisStart) {
+		// This month is longer, so all of last month counts
+		lastSoFar = thisStart
+	}
+
+	perCat := map[*api.Category]float64{}
+	for _, t := range ts {
+		at := t.AuthedAt
+		if at.Before(lastStart) || at.After(now) {
+			continue
+		}
+
+		spent := max(-t.Amount, 0)
+		if at.Before(thisStart) {
+			s.lastMonth += spent
+			if at.Before(lastSoFar) {
+				s.lastMonthSoFar += spent
+			}
+			continue
+		}
+
+		s.thisMonth += spent
+
+		var cat *api.Category
+		if t.ResolvedCategoryID != nil {
+			cat = cache.Category(*t.ResolvedCategoryID)
+		}
+		if cat == nil {
+			s.uncategorised++
+			s.uncategorisedSpent += spent
+		} else if spent > 0 {
+			perCat[cat] += spent
+		}
+
+		if spent > 0 {
+			s.largest = append(s.largest, t)
+		}
+	}
+
+	for c, v := range perCat {
+		s.categories = append(s.categories, categorySpend{cat: c, spent: v})
+	}
+	slices.SortFunc(s.categories, func(a, b categorySpend) int {
+		if c := cmp.Compare(b.spent, a.spent); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.cat.Name, b.cat.Name)
+	})
+
+	slices.SortStableFunc(s.largest, func(a, b *api.Transaction) int {
+		if c := cmp.Compare(a.Amount, b.Amount); c != 0 {
+			return c
+		}
+		return b.AuthedAt.Compare(a.AuthedAt)
+	})
+	s.largest = s.largest[:min(len(s.largest), LARGEST_COUNT)]
+
+	return s
+}
@@ -0,0 +1,129 @@
+package dashboard
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bank_data_tui/api"
+	"github.com/bank_data_tui/utils/repo"
+)
+
+func tx(at time.Time, amount float64, cat string) *api.Transaction {
+	t := &api.Transaction{AuthedAt: at, Amount: amount}
+	if cat != "" {
+		t.ResolvedCategoryID = &cat
+	}
+
+	return t
+}
+
+func TestSummarise(t *testing.T) {
+	cache := &repo.Cache{Categories: []*api.Category{{ID: "1", SavableCategory: api.SavableCategory{Name: "Groceries"}}}}
+	day := func(m time.Month, d, h int) time.Time { return time.Date(2024, m, d, h, 0, 0, 0, time.UTC) }
+
+	for _, c := range []struct {
+		name string
+		now  time.Time
+		ts   []*api.Transaction
+
+		thisMonth, lastMonthSoFar, lastMonth float64
+		uncategorised                        int
+		uncategorisedSpent                   float64
+	}{
+		{
+			name: "so far is up to the same time last month",
+			now:  day(3, 15, 12),
+			ts: []*api.Transaction{
+				tx(day(2, 15, 11), -10, "1"),
+				tx(day(2, 15, 13), -20, "1"),
+				tx(day(3, 1, 0), -5, "1"),
+			},
+			thisMonth: 5, lastMonthSoFar: 10, lastMonth: 30,
+		},
+		{
+			// Mar 31 is past the end of Feb, so all of it counts
+			name: "capped at a shorter last month",
+			now:  day(3, 31, 12),
+			ts: []*api.Transaction{
+				tx(day(2, 29, 23), -10, "1"),
+				tx(day(3, 1, 0), -5, "1"),
+			},
+			thisMonth: 5, lastMonthSoFar: 10, lastMonth: 10,
+		},
+		{
+			name: "outside of the two months",
+			now:  day(3, 15, 12),
+			ts: []*api.Transaction{
+				tx(day(1, 31, 23), -10, "1"),
+				tx(day(3, 15, 13), -20, "1"),
+			},
+		},
+		{
+			name: "income isn't spending",
+			now:  day(3, 15, 12),
+			ts: []*api.Transaction{
+				tx(day(2, 10, 0), 100, "1"),
+				tx(day(3, 10, 0), 100, "1"),
+			},
+		},
+		{
+			name: "uncategorised, or in a category that's gone",
+			now:  day(3, 15, 12),
+			ts: []*api.Transaction{
+				tx(day(3, 2, 0), -10, ""),
+				tx(day(3, 3, 0), -5, "deleted"),
+				// Counted, but isn't spending
+				tx(day(3, 4, 0), 50, ""),
+				// Last month's don't count
+				tx(day(2, 4, 0), -7, ""),
+			},
+			thisMonth: 15, lastMonthSoFar: 7, lastMonth: 7,
+			uncategorised: 3, uncategorisedSpent: 15,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			s := summarise(c.ts, cache, c.now)
+			if s.thisMonth != c.thisMonth || s.lastMonthSoFar != c.lastMonthSoFar || s.lastMonth != c.lastMonth {
+				t.Errorf("this month %v, last month so far %v, last month %v, want %v, %v, %v",
+					s.thisMonth, s.lastMonthSoFar, s.lastMonth, c.thisMonth, c.lastMonthSoFar, c.lastMonth)
+			}
+			if s.uncategorised != c.uncategorised || s.uncategorisedSpent != c.uncategorisedSpent {
+				t.Errorf("uncategorised %d (%v), want %d (%v)",
+					s.uncategorised, s.uncategorisedSpent, c.uncategorised, c.uncategorisedSpent)
+			}
+		})
+	}
+}
+
+func TestSummariseCategories(t *testing.T) {
+	cache := &repo.Cache{Categories: []*api.Category{
+		{ID: "1", SavableCategory: api.SavableCategory{Name: "Groceries"}},
+		{ID: "2", SavableCategory: api.SavableCategory{Name: "Bills"}},
+		{ID: "3", SavableCategory: api.SavableCategory{Name: "Fun"}},
+	}}
+	now := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+	ts := []*api.Transaction{
+		tx(now.AddDate(0, 0, -1), -10, "1"),
+		tx(now.AddDate(0, 0, -2), -10, "2"),
+		tx(now.AddDate(0, 0, -3), -5, "1"),
+		// Income only, so it isn't listed
+		tx(now.AddDate(0, 0, -4), 30, "3"),
+	}
+
+	s := summarise(ts, cache, now)
+	want := []struct {
+		name  string
+		spent float64
+	}{{"Groceries", 15}, {"Bills", 10}}
+	if len(s.categories) != len(want) {
+		t.Fatalf("got %d categories, want %d", len(s.categories), len(want))
+	}
+	for i, w := range want {
+		if got := s.categories[i]; got.cat.Name != w.name || got.spent != w.spent {
+			t.Errorf("categories[%d] = %s %v, want %s %v", i, got.cat.Name, got.spent, w.name, w.spent)
+		}
+	}
+	if len(s.largest) != 3 || s.largest[0].Amount != -10 || s.largest[2].Amount != -5 {
+		t.Errorf("largest isn't biggest first: %v", s.largest)
+	}
+}
@@ -0,0 +1,29 @@
+package login
+
+import (
+	"charm.land/bubbles/v2/key"
+	"github.com/bank_data_tui/utils/keymap"
+)
+
+var (
+	KEY_NEXT   = key.NewBinding(key.WithKeys("tab", "down"), key.WithHelp("tab", "next field"))
+	KEY_PREV   = key.NewBinding(key.WithKeys("shift+tab", "up"), key.WithHelp("shift+tab", "previous field"))
+	KEY_SUBMIT = key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "next field / login"))
+)
+
+func init() {
+	keymap.Register("login", map[string]*key.Binding{
+		"next":   &KEY_NEXT,
+		"prev":   &KEY_PREV,
+		"submit": &KEY_SUBMIT,
+	})
+}
+
+func (s Model) KeyHelp() []key.Binding {
+	return keymap.Group("login")
+}
+
+// Field 2 is the login button
+func (s Model) Typing() bool {
+	return s.focusedField != 2
+}
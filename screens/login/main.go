@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"time"
 
+	"charm.land/bubbles/v2/key"
 	"charm.land/bubbles/v2/textinput"
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
@@ -28,6 +29,9 @@ type Model struct {
 
 	inpName textinput.Model
 	inpPass textinput.Model
+
+	// Shown above the fields, ie. why the user is back here
+	banner string
 }
 
 func NewScreenLogin() *Model {
@@ -98,6 +102,12 @@ func (s Model) View() (string, *tea.Cursor) {
 		}
 	}
 
+	banner := ""
+	if s.banner != "" {
+		// Wrapped to the fields' width, so that they stay where the cursor expects them
+		banner = styles.S_TEXT_HIGHLIGHT_SECONDARY.Width(lipgloss.Width(fieldStyle.Render(name))).Align(lipgloss.Center).Render(s.banner)
+	}
+
 	var c *tea.Cursor
 
 	if s.state == 0 && s.focusedField != 2 {
@@ -113,6 +123,9 @@ func (s Model) View() (string, *tea.Cursor) {
 			if s.focusedField == 1 {
 				c.Y += 4
 			}
+			if banner != "" {
+				c.Y += lipgloss.Height(banner) + 1
+			}
 		}
 	}
 
@@ -125,14 +138,12 @@ func (s Model) View() (string, *tea.Cursor) {
 		fieldStyle = fieldStyle.Inherit(STYLE_MOD_WRONG)
 	}
 
-	return lipgloss.JoinVertical(
-		lipgloss.Center,
-		fieldStyle.Render(name),
-		"",
-		fieldStyle.Render(pass),
-		"",
-		btnStyle.Render("Login"),
-	), c
+	parts := []string{fieldStyle.Render(name), "", fieldStyle.Render(pass), "", btnStyle.Render("Login")}
+	if banner != "" {
+		parts = append([]string{banner, ""}, parts...)
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Center, parts...), c
 }
 
 // [username, password]
@@ -168,12 +179,12 @@ func (s *Model) Update(msg tea.Msg) (utils.Screen, tea.Cmd) {
 	switch m := msg.(type) {
 	case tea.KeyPressMsg:
 		if s.state != 1 {
-			switch m.String() {
-			case "tab", "down":
+			switch {
+			case key.Matches(m, KEY_NEXT):
 				batcher = append(batcher, s.changeField(s.focusedField+1))
-			case "shift+tab", "up":
+			case key.Matches(m, KEY_PREV):
 				batcher = append(batcher, s.changeField(s.focusedField-1))
-			case "enter":
+			case key.Matches(m, KEY_SUBMIT):
 				if s.focusedField == 2 && s.inpName.Err == nil && s.inpPass.Err == nil {
 					s.state = 1
 
@@ -202,6 +213,17 @@ func (s *Model) Update(msg tea.Msg) (utils.Screen, tea.Cmd) {
 
 type clearWrongPass bool
 
+// Shows text above the fields, ie. why the user has to log in
+func (s *Model) SetBanner(text string) {
+	s.banner = text
+}
+
+// Fills in the username & moves on to the password
+func (s *Model) SetUsername(name string) tea.Cmd {
+	s.inpName.SetValue(name)
+	return s.changeField(1)
+}
+
 func (s *Model) WrongPassword() tea.Cmd {
 	s.state = 2
 	s.inpName.SetValue("")
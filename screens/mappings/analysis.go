@@ -0,0 +1,309 @@
+package mappings
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/bank_data_tui/api"
+	"github.com/bank_data_tui/config"
+	"github.com/bank_data_tui/styles"
+	"github.com/bank_data_tui/utils"
+	"github.com/bank_data_tui/utils/listeditor"
+)
+
+type overlap struct {
+	a, b  *api.Mapping
+	count int
+}
+
+// A mapping that matches things, but never wins
+type beatenMapping struct {
+	m *api.Mapping
+	// The mapping that beats it most often
+	by      *api.Mapping
+	matched int
+}
+
+type analysis struct {
+	mappings     int
+	transactions int
+	unmatched    []*api.Transaction
+	unused       []*api.Mapping
+	beaten       []beatenMapping
+	overlaps     []overlap
+}
+
+// Runs every mapping against ts, the same way the server would
+func analyse(ms []*api.Mapping, ts []*api.Transaction) *analysis {
+	res := &analysis{
+		mappings:     len(ms),
+		transactions: len(ts),
+	}
+
+	matchCount := map[*api.Mapping]int{}
+	winCount := map[*api.Mapping]int{}
+	beatenBy := map[*api.Mapping]map[*api.Mapping]int{}
+	overlaps := map[[2]*api.Mapping]int{}
+
+	for _, t := range ts {
+		matching := []*api.Mapping{}
+		for _, m := range ms {
+			if m.Matches(t) {
+				matching = append(matching, m)
+				matchCount[m]++
+			}
+		}
+
+		if len(matching) == 0 {
+			res.unmatched = append(res.unmatched, t)
+			continue
+		}
+
+		winner := api.ResolvingMapping(matching, t)
+		winCount[winner]++
+
+		for i, a := range matching {
+			if a != winner {
+				if beatenBy[a] == nil {
+					beatenBy[a] = map[*api.Mapping]int{}
+				}
+				beatenBy[a][winner]++
+			}
+
+			for _, b := range matching[i+1:] {
+				overlaps[[2]*api.Mapping{a, b}]++
+			}
+		}
+	}
+
+	for _, m := range ms {
+		switch {
+		case matchCount[m] == 0:
+			res.unused = append(res.unused, m)
+		case winCount[m] == 0:
+			b := beatenMapping{m: m, matched: matchCount[m]}
+			best := 0
+			for by, n := range beatenBy[m] {
+				if n > best || (n == best && by.Priority > b.by.Priority) {
+					best = n
+					b.by = by
+				}
+			}
+			res.beaten = append(res.beaten, b)
+		}
+	}
+
+	for pair, n := range overlaps {
+		res.overlaps = append(res.overlaps, overlap{a: pair[0], b: pair[1], count: n})
+	}
+	slices.SortFunc(res.overlaps, func(a, b overlap) int {
+		if c := cmp.Compare(b.count, a.count); c != 0 {
+			return c
+		}
+		return strings.Compare(a.a.Name+a.b.Name, b.a.Name+b.b.Name)
+	})
+
+	return res
+}
+
+type reportRow struct {
+	text string
+	// Mapping this row points to, "" for headings & such
+	id string
+	// Or the transaction, that a new mapping would be made for
+	t *api.Transaction
+}
+
+func (r reportRow) selectable() bool {
+	return r.id != "" || r.t != nil
+}
+
+var (
+	STYLE_REPORT_HEADING lipgloss.Style
+	STYLE_REPORT_BAD     lipgloss.Style
+)
+
+func init() {
+	styles.OnTheme(func() {
+		STYLE_REPORT_HEADING = lipgloss.NewStyle().Bold(true).Foreground(styles.COLOR_MAIN)
+		STYLE_REPORT_BAD = lipgloss.NewStyle().Foreground(styles.COLOR_WRONG)
+	})
+}
+
+func (a *analysis) rows(cfg *config.Config) []reportRow {
+	rows := []reportRow{
+		{text: styles.S_TEXT_DISABLED.Render(
+			strconv.Itoa(a.mappings) + " mappings against " + strconv.Itoa(a.transactions) + " recent transactions",
+		)},
+		{},
+		{text: STYLE_REPORT_HEADING.Render("Never match (" + strconv.Itoa(len(a.unused)) + ")")},
+	}
+	for _, m := range a.unused {
+		rows = append(rows, reportRow{text: m.Name, id: m.ID})
+	}
+
+	rows = append(rows, reportRow{}, reportRow{
+		text: STYLE_REPORT_HEADING.Render("Always beaten (" + strconv.Itoa(len(a.beaten)) + ")"),
+	})
+	for _, b := range a.beaten {
+		rows = append(rows, reportRow{
+			text: b.m.Name + styles.S_TEXT_DISABLED.Render(
+				" by "+b.by.Name+" ("+strconv.Itoa(b.matched)+" matches)",
+			),
+			id: b.m.ID,
+		})
+	}
+
+	rows = append(rows, reportRow{}, reportRow{
+		text: STYLE_REPORT_HEADING.Render("Overlaps (" + strconv.Itoa(len(a.overlaps)) + ")"),
+	})
+	for _, o := range a.overlaps {
+		// Jump to the loser, since that's the one that is probably wrong
+		winner, loser := o.a, o.b
+		if loser.Priority > winner.Priority {
+			winner, loser = loser, winner
+		}
+
+		txt := winner.Name + " & " + loser.Name + styles.S_TEXT_DISABLED.Render(
+			" ("+strconv.Itoa(o.count)+" transactions)",
+		)
+		if winner.Priority == loser.Priority {
+			txt += STYLE_REPORT_BAD.Render(" same priority!")
+		}
+
+		rows = append(rows, reportRow{text: txt, id: loser.ID})
+	}
+
+	// Last, since there's usually the most of these
+	rows = append(rows, reportRow{}, reportRow{
+		text: STYLE_REPORT_HEADING.Render("Unmatched transactions (" + strconv.Itoa(len(a.unmatched)) + ")"),
+	})
+	for _, t := range a.unmatched {
+		rows = append(rows, reportRow{
+			text: styles.S_TEXT_DISABLED.Render(cfg.FormatDate(t.AuthedAt)+" ") + t.Desc +
+				styles.S_TEXT_DISABLED.Render(" "+cfg.Amount.Format(t.Amount)),
+			t: t,
+		})
+	}
+
+	return rows
+}
+
+func (m *mappingImpl) openAnalysis() {
+	all := make([]*api.Mapping, len(m.mappings))
+	for i, v := range m.mappings {
+		all[i] = (*api.Mapping)(v)
+	}
+
+	m.analysisOpen = true
+	m.analysisSel = 0
+	m.analysisOff = 0
+	m.report = nil
+	if m.cache.Sample != nil {
+		m.report = analyse(all, m.cache.Sample).rows(m.cfg)
+		m.analysisSel = m.nextSelectable(-1, 1)
+	}
+	m.scrollAnalysis()
+}
+
+// Next row with a mapping (or transaction) from i in dir, or i if there is none
+func (m *mappingImpl) nextSelectable(i, dir int) int {
+	for j := i + dir; j >= 0 && j < len(m.report); j += dir {
+		if m.report[j].selectable() {
+			return j
+		}
+	}
+
+	return i
+}
+
+// Rows of the report that fit under the title & the empty line
+func (m *mappingImpl) analysisRowsH() int {
+	return max(m.h-2, 1)
+}
+
+// Keeps the selected row in view
+func (m *mappingImpl) scrollAnalysis() {
+	rowsH := m.analysisRowsH()
+	if m.analysisSel < m.analysisOff {
+		m.analysisOff = m.analysisSel
+	} else if m.analysisSel >= m.analysisOff+rowsH {
+		m.analysisOff = m.analysisSel - rowsH + 1
+	}
+	m.analysisOff = max(min(m.analysisOff, len(m.report)-1), 0)
+}
+
+func (m *mappingImpl) handleAnalysisKey(msg tea.KeyPressMsg) (tea.Cmd, bool) {
+	switch {
+	case key.Matches(msg, KEY_ANALYSIS_CLOSE):
+		m.analysisOpen = false
+	case key.Matches(msg, KEY_ANALYSIS_UP):
+		m.analysisSel = m.nextSelectable(m.analysisSel, -1)
+		m.scrollAnalysis()
+	case key.Matches(msg, KEY_ANALYSIS_DOWN):
+		m.analysisSel = m.nextSelectable(m.analysisSel, 1)
+		m.scrollAnalysis()
+	case key.Matches(msg, KEY_ANALYSIS_JUMP):
+		if m.analysisSel < 0 || m.analysisSel >= len(m.report) || !m.report[m.analysisSel].selectable() {
+			break
+		}
+
+		m.analysisOpen = false
+		r := m.report[m.analysisSel]
+		if r.t != nil {
+			v := m.mappingFor(r.t)
+			return func() tea.Msg { return listeditor.EditNew{Value: v} }, true
+		}
+
+		return func() tea.Msg { return listeditor.SelectItem(r.id) }, true
+	}
+
+	// Everything is eaten while open, so that the editor behind doesn't get typed into
+	return nil, true
+}
+
+// A new mapping that would match just t, like the one the transactions screen makes
+func (m *mappingImpl) mappingFor(t *api.Transaction) *mappingProxy {
+	all := make([]*api.Mapping, len(m.mappings))
+	for i, v := range m.mappings {
+		all[i] = (*api.Mapping)(v)
+	}
+
+	v := &mappingProxy{Name: t.Desc, InpText: DescRegex(t.Desc), Priority: NextPriority(all)}
+	if t.ResolvedName != nil {
+		v.ResName = *t.ResolvedName
+	}
+
+	return v
+}
+
+func (m *mappingImpl) renderAnalysis(w, h int) string {
+	title := STYLE_PREVIEW_TITLE.Render("Analysis") + styles.S_TEXT_DISABLED.Render(" "+KEY_ANALYSIS_JUMP.Help().Key+" to jump, "+KEY_ANALYSIS_CLOSE.Help().Key+" to close")
+	if m.report == nil {
+		return title + "\n\n" + styles.S_TEXT_DISABLED.Render("Transactions aren't loaded yet, try again in a bit")
+	}
+
+	// title + empty line
+	rowsH := h - 2
+
+	lines := []string{utils.Overflow(title, w), ""}
+	for i, r := range m.report[m.analysisOff:] {
+		if i == rowsH {
+			break
+		}
+
+		txt := "  " + r.text
+		if m.analysisOff+i == m.analysisSel && r.selectable() {
+			txt = STYLE_REPORT_HEADING.Render("> ") + lipgloss.NewStyle().Underline(true).Render(r.text)
+		}
+
+		lines = append(lines, utils.Overflow(txt, w))
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -1,6 +1,7 @@
 package mappings
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"slices"
@@ -11,6 +12,7 @@ import (
 	"github.com/bank_data_tui/api"
 	"github.com/bank_data_tui/utils/editor"
 	"github.com/bank_data_tui/utils/listeditor"
+	"github.com/bank_data_tui/utils/repo"
 )
 
 type mappingProxy api.Mapping
@@ -25,9 +27,48 @@ func (m *mappingProxy) SetID(id string) {
 	m.ID = id
 }
 
-func (c *mappingImpl) NewEditor(w, h int, v *mappingProxy) *editor.Model {
+// Where each field is in the editor, for its validators & reading what's typed
+const (
+	FIELD_NAME = iota
+	FIELD_PRIORITY
+	FIELD_REGEX
+	FIELD_AMOUNT
+	FIELD_RES_NAME
+	FIELD_RES_CATEGORY
+)
+
+func (c *mappingImpl) NewEditor(ctx context.Context, w, h int, v *mappingProxy) *editor.Model {
+	return c.newEditor(ctx, w-listeditor.WIDTH_OFFSET_EDITOR, v)
+}
+
+// A mapping editor for use outside of the mappings screen, w is the width of the editor itself
+func NewEditor(ctx context.Context, c *api.APIClient, cache *repo.Cache, w int, v *api.Mapping) *editor.Model {
+	impl := &mappingImpl{
+		api:   c,
+		cache: cache,
+	}
+
+	return impl.newEditor(ctx, w, (*mappingProxy)(v))
+}
+
+// Regex that matches exactly desc
+func DescRegex(desc string) string {
+	return "^" + regexp.QuoteMeta(desc) + "$"
+}
+
+// Priority that beats every existing mapping
+func NextPriority(ms []*api.Mapping) int {
+	p := 0
+	for _, m := range ms {
+		p = max(p, m.Priority)
+	}
+
+	return p + 1
+}
+
+func (c *mappingImpl) newEditor(ctx context.Context, w int, v *mappingProxy) *editor.Model {
 	return editor.New(
-		w-listeditor.WIDTH_OFFSET_EDITOR,
+		w,
 		v.ID,
 		[]*editor.DataField{
 			{
@@ -69,7 +110,7 @@ func (c *mappingImpl) NewEditor(w, h int, v *mappingProxy) *editor.Model {
 						return ""
 					}
 
-					return strconv.FormatFloat(*v.InpAmt, 'e', 2, 64)
+					return strconv.FormatFloat(*v.InpAmt, 'f', -1, 64)
 				},
 				SetValue: func(raw string) {
 					if raw == "" {
@@ -128,30 +169,30 @@ func (c *mappingImpl) NewEditor(w, h int, v *mappingProxy) *editor.Model {
 			},
 		},
 		func(alt bool) (string, error) {
-			id, err := c.api.MappingsCreate((*api.Mapping)(v), alt)
+			id, err := c.api.MappingsCreate(ctx, (*api.Mapping)(v), alt)
 			if err != nil {
 				return "", err
 			}
 			return id, nil
 		},
 		func(alt bool, id string) error {
-			err := c.api.MappingsUpdate(id, (*api.Mapping)(v), alt)
+			err := c.api.MappingsUpdate(ctx, id, (*api.Mapping)(v), alt)
 			if err != nil {
 				return err
 			}
 			return nil
 		},
 		func(alt bool, id string) error {
-			err := c.api.MappingsDelete(id, alt)
+			err := c.api.MappingsDelete(ctx, id, alt)
 			if err != nil {
 				return err
 			}
 			return nil
 		},
-		editor.RequireFields(0),
-		editor.AddIntValidator(1),
-		editor.AddFloatValidator(3),
-		editor.AddFieldValidator(2, func(s string) error {
+		editor.RequireFields(FIELD_NAME),
+		editor.AddIntValidator(FIELD_PRIORITY),
+		editor.AddFloatValidator(FIELD_AMOUNT),
+		editor.AddFieldValidator(FIELD_REGEX, func(s string) error {
 			if s == "" {
 				return nil
 			}
@@ -162,7 +203,7 @@ func (c *mappingImpl) NewEditor(w, h int, v *mappingProxy) *editor.Model {
 
 			return nil
 		}),
-		editor.AddFieldValidator(5, func(s string) error {
+		editor.AddFieldValidator(FIELD_RES_CATEGORY, func(s string) error {
 			if s == "" {
 				return nil
 			}
@@ -176,11 +217,11 @@ func (c *mappingImpl) NewEditor(w, h int, v *mappingProxy) *editor.Model {
 			return fmt.Errorf("Must be a valid category")
 		}),
 		func(fields []*textinput.Model) {
-			fields[5].ShowSuggestions = true
-			c.categoryField = fields[5]
+			fields[FIELD_RES_CATEGORY].ShowSuggestions = true
+			c.categoryField = fields[FIELD_RES_CATEGORY]
 			c.resetSuggestions()
 		},
-		editor.AddOneOfRequirement("matcher", 2, 3),
-		editor.AddOneOfRequirement("result", 4, 5),
+		editor.AddOneOfRequirement("matcher", FIELD_REGEX, FIELD_AMOUNT),
+		editor.AddOneOfRequirement("result", FIELD_RES_NAME, FIELD_RES_CATEGORY),
 	)
 }
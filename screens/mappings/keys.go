@@ -0,0 +1,56 @@
+package mappings
+
+import (
+	"charm.land/bubbles/v2/key"
+	"github.com/bank_data_tui/utils/keymap"
+)
+
+var (
+	KEY_ANALYSIS   = key.NewBinding(key.WithKeys("alt+a"), key.WithHelp("alt+a", "analysis"))
+	KEY_MOVE_UP    = key.NewBinding(key.WithKeys("alt+shift+up"), key.WithHelp("alt+shift+↑", "raise priority"))
+	KEY_MOVE_DOWN  = key.NewBinding(key.WithKeys("alt+shift+down"), key.WithHelp("alt+shift+↓", "lower priority"))
+	KEY_SAVE_ORDER = key.NewBinding(key.WithKeys("alt+s"), key.WithHelp("alt+s", "save priorities"))
+
+	// While the analysis is open
+	KEY_ANALYSIS_CLOSE = key.NewBinding(key.WithKeys("alt+a", "esc"), key.WithHelp("esc", "close"))
+	KEY_ANALYSIS_UP    = key.NewBinding(key.WithKeys("up"), key.WithHelp("↑", "previous mapping"))
+	KEY_ANALYSIS_DOWN  = key.NewBinding(key.WithKeys("down"), key.WithHelp("↓", "next mapping"))
+	KEY_ANALYSIS_JUMP  = key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "jump"))
+
+	// While confirming new priorities
+	KEY_ORDER_SAVE     = key.NewBinding(key.WithKeys("enter", "r"), key.WithHelp("enter/r", "save & re-run on old transactions"))
+	KEY_ORDER_SAVE_NEW = key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "save for new transactions only"))
+	KEY_ORDER_CANCEL   = key.NewBinding(key.WithKeys("esc", "alt+s"), key.WithHelp("esc", "keep editing"))
+)
+
+func init() {
+	keymap.Register("mappings", map[string]*key.Binding{
+		"analysis":   &KEY_ANALYSIS,
+		"move_up":    &KEY_MOVE_UP,
+		"move_down":  &KEY_MOVE_DOWN,
+		"save_order": &KEY_SAVE_ORDER,
+	})
+	keymap.Register("mappings.analysis", map[string]*key.Binding{
+		"close": &KEY_ANALYSIS_CLOSE,
+		"up":    &KEY_ANALYSIS_UP,
+		"down":  &KEY_ANALYSIS_DOWN,
+		"jump":  &KEY_ANALYSIS_JUMP,
+	})
+	keymap.Register("mappings.order", map[string]*key.Binding{
+		"save":          &KEY_ORDER_SAVE,
+		"save_new_only": &KEY_ORDER_SAVE_NEW,
+		"cancel":        &KEY_ORDER_CANCEL,
+	})
+	keymap.Related("mappings", "listeditor", "editor")
+}
+
+func (m *mappingImpl) KeyHelp() []key.Binding {
+	switch {
+	case m.analysisOpen:
+		return keymap.Group("mappings.analysis")
+	case m.confirmOpen:
+		return keymap.Group("mappings.order")
+	}
+
+	return keymap.Group("mappings")
+}
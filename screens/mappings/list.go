@@ -2,6 +2,7 @@ package mappings
 
 import (
 	"io"
+	"strconv"
 
 	"charm.land/bubbles/v2/list"
 	tea "charm.land/bubbletea/v2"
@@ -11,12 +12,14 @@ import (
 	"github.com/bank_data_tui/utils/listeditor"
 )
 
-type mappingDelegate struct{}
+type mappingDelegate struct {
+	impl *mappingImpl
+}
 
 func (mappingDelegate) Spacing() int { return 1 }
 func (mappingDelegate) Height() int  { return 1 }
 
-func (mappingDelegate) Render(w io.Writer, m list.Model, i int, v list.Item) {
+func (d mappingDelegate) Render(w io.Writer, m list.Model, i int, v list.Item) {
 	style := lipgloss.NewStyle().Foreground(styles.COLOR_MAIN)
 	if m.GlobalIndex() == i {
 		style = style.Underline(true)
@@ -32,9 +35,15 @@ func (mappingDelegate) Render(w io.Writer, m list.Model, i int, v list.Item) {
 	}
 
 	val := v.(*mappingProxy)
+	prio := strconv.Itoa(val.Priority)
+	if d.impl.dirty[val.ID] {
+		prio = "*" + prio
+	}
+	nameW := listeditor.WIDTH_LIST - 1 - len(prio) - 1
+
 	w.Write([]byte(" " + style.Render(
-		utils.Overflow(val.Name, listeditor.WIDTH_LIST - 1),
-	)))
+		lipgloss.NewStyle().Width(nameW).Render(utils.Overflow(val.Name, nameW)),
+	) + " " + styles.S_TEXT_DISABLED.Render(prio)))
 }
 
 func (mappingDelegate) Update(msg tea.Msg, m *list.Model) tea.Cmd { return nil }
@@ -1,21 +1,46 @@
 package mappings
 
 import (
+	"context"
+	"slices"
+
+	"charm.land/bubbles/v2/key"
 	"charm.land/bubbles/v2/textinput"
 	tea "charm.land/bubbletea/v2"
 	"github.com/bank_data_tui/api"
+	"github.com/bank_data_tui/config"
+	"github.com/bank_data_tui/utils"
+	"github.com/bank_data_tui/utils/editor"
 	"github.com/bank_data_tui/utils/listeditor"
+	"github.com/bank_data_tui/utils/notify"
 	"github.com/bank_data_tui/utils/repo"
 )
 
 type mappingImpl struct {
+	ctx           context.Context
+	cfg           *config.Config
 	cache         *repo.Cache
 	api           *api.APIClient
 	categoryField *textinput.Model
+	// Same items as the list editor, kept for matching against each other
+	mappings []*mappingProxy
+	// IDs of mappings that were moved, but aren't saved yet
+	dirty       map[string]bool
+	confirmOpen bool
+
+	analysisOpen bool
+	report       []reportRow
+	analysisSel  int
+	analysisOff  int
+	// Of the side panel, for scrolling the analysis
+	h int
+
+	// nil when it has to be worked out again
+	preview *preview
 }
 
-func (m *mappingImpl) InitialFetch() ([]*mappingProxy, error) {
-	all, err := m.api.MappingsFetch()
+func (m *mappingImpl) InitialFetch(ctx context.Context) ([]*mappingProxy, error) {
+	all, err := m.api.MappingsFetch(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -29,11 +54,33 @@ func (m *mappingImpl) InitialFetch() ([]*mappingProxy, error) {
 
 type absSetup struct{}
 
-func (m *mappingImpl) Init() tea.Cmd {
+type sampleLoaded []*api.Transaction
+
+// Ran again on focus, along with the mappings refetch
+func (m *mappingImpl) Init(ctx context.Context) tea.Cmd {
+	m.ctx = ctx
+	// Transactions could've been uploaded or overridden since
+	m.cache.Sample = nil
+
+	return tea.Batch(m.fetchCategories(ctx), m.fetchSample(ctx))
+}
+
+func (m *mappingImpl) fetchSample(ctx context.Context) tea.Cmd {
+	return func() tea.Msg {
+		s, err := repo.FetchSample(ctx, m.api)
+		if err != nil {
+			return notify.Error(err, m.fetchSample(ctx))
+		}
+
+		return sampleLoaded(s)
+	}
+}
+
+func (m *mappingImpl) fetchCategories(ctx context.Context) tea.Cmd {
 	return func() tea.Msg {
-		_, err := m.cache.EasyCategories(m.api)
+		_, err := m.cache.EasyCategories(ctx, m.api)
 		if err != nil {
-			panic(err)
+			return notify.Error(err, m.fetchCategories(ctx))
 		}
 
 		return absSetup{}
@@ -41,12 +88,104 @@ func (m *mappingImpl) Init() tea.Cmd {
 }
 
 func (m *mappingImpl) Update(msg tea.Msg) {
-	switch msg.(type) {
+	switch msg := msg.(type) {
 	case absSetup:
 		m.resetSuggestions()
+	case utils.ResizeMessage:
+		m.h = msg.H
+		m.scrollAnalysis()
+	case listeditor.ItemsLoaded:
+		m.mappings = slices.Clone(msg.Items.([]*mappingProxy))
+	case sampleLoaded:
+		m.cache.Sample = msg
+		if m.analysisOpen {
+			m.openAnalysis()
+		}
+	case orderSaved:
+		for _, id := range msg.ids {
+			delete(m.dirty, id)
+		}
+		if len(msg.ids) != 0 {
+			m.cache.Mappings = nil
+		}
+	case editor.ItemDel:
+		m.mappings = slices.DeleteFunc(m.mappings, func(v *mappingProxy) bool { return v.ID == string(msg) })
+		delete(m.dirty, string(msg))
+	case listeditor.ItemNew:
+		m.mappings = append(m.mappings, msg.Value.(*mappingProxy))
+		slices.SortStableFunc(m.mappings, m.Compare)
+	case listeditor.ItemUpdate:
+		// The editor saves the whole thing, priority included
+		delete(m.dirty, msg.Value.(*mappingProxy).ID)
+		slices.SortStableFunc(m.mappings, m.Compare)
+	}
+
+	switch msg.(type) {
+	case editor.ItemDel, listeditor.ItemNew, listeditor.ItemUpdate:
+		// Other screens match against these, so they need a refetch
+		m.cache.Mappings = nil
+	}
+
+	switch msg.(type) {
+	case sampleLoaded, listeditor.ItemsLoaded, orderSaved, editor.ItemDel, listeditor.ItemNew, listeditor.ItemUpdate:
+		// What it's matched against changed
+		m.preview = nil
 	}
 }
 
+// Every mapping (other than the one with skipID) that beats priority
+func (m *mappingImpl) higherPriority(skipID string, priority int) []*api.Mapping {
+	res := []*api.Mapping{}
+	for _, v := range m.mappings {
+		if v.ID != skipID && v.Priority > priority {
+			res = append(res, (*api.Mapping)(v))
+		}
+	}
+
+	return res
+}
+
+func (m *mappingImpl) HandleKey(msg tea.KeyPressMsg, cur *mappingProxy) (tea.Cmd, bool) {
+	switch {
+	case m.analysisOpen:
+		return m.handleAnalysisKey(msg)
+	case m.confirmOpen:
+		return m.handleOrderKey(msg)
+	}
+
+	switch {
+	case key.Matches(msg, KEY_ANALYSIS):
+		m.openAnalysis()
+	case key.Matches(msg, KEY_MOVE_UP):
+		return m.move(cur, -1), true
+	case key.Matches(msg, KEY_MOVE_DOWN):
+		return m.move(cur, 1), true
+	case key.Matches(msg, KEY_SAVE_ORDER):
+		m.confirmOpen = len(m.dirty) != 0
+	default:
+		return nil, false
+	}
+
+	return nil, true
+}
+
+// Moved mappings would lose their new priorities
+func (m *mappingImpl) RefreshOnFocus() bool {
+	return len(m.dirty) == 0
+}
+
+func (m *mappingImpl) SidePanelOpen() bool {
+	return m.analysisOpen || m.confirmOpen
+}
+
+func (m *mappingImpl) SidePanel(w, h int) string {
+	if m.confirmOpen {
+		return m.renderOrderConfirm(w)
+	}
+
+	return m.renderAnalysis(w, h)
+}
+
 func (m *mappingImpl) resetSuggestions() {
 	sl := make([]string, len(m.cache.Categories))
 	for i, v := range m.cache.Categories {
@@ -56,14 +195,19 @@ func (m *mappingImpl) resetSuggestions() {
 	m.categoryField.SetSuggestions(sl)
 }
 
-func New(c *api.APIClient, cache *repo.Cache, w, h int) *listeditor.Model[mappingProxy, *mappingProxy] {
-	m := listeditor.New[mappingProxy](
-		"New Mapping", mappingDelegate{}, w, h,
-	)
-	m.Abstraction = &mappingImpl{
+func New(ctx context.Context, c *api.APIClient, cache *repo.Cache, cfg *config.Config, w, h int) *listeditor.Model[mappingProxy, *mappingProxy] {
+	impl := &mappingImpl{
+		ctx:   ctx,
+		cfg:   cfg,
 		api:   c,
 		cache: cache,
+		dirty: map[string]bool{},
+		h:     h,
 	}
+	m := listeditor.New[mappingProxy](
+		ctx, "New Mapping", mappingDelegate{impl: impl}, w, h,
+	)
+	m.Abstraction = impl
 
 	return m
 }
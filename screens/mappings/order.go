@@ -0,0 +1,139 @@
+package mappings
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+	"github.com/bank_data_tui/api"
+	"github.com/bank_data_tui/styles"
+	"github.com/bank_data_tui/utils"
+	"github.com/bank_data_tui/utils/listeditor"
+	"github.com/bank_data_tui/utils/notify"
+)
+
+type orderSaved struct {
+	ids []string
+}
+
+// Evaluation order, the highest priority is checked first
+func (m *mappingImpl) Compare(a, b *mappingProxy) int {
+	if c := cmp.Compare(b.Priority, a.Priority); c != 0 {
+		return c
+	}
+
+	return strings.Compare(a.Name, b.Name)
+}
+
+// Swaps cur with its neighbour in dir (-1 is up/higher priority), renumbering whatever is needed to keep the order
+func (m *mappingImpl) move(cur *mappingProxy, dir int) tea.Cmd {
+	i := slices.Index(m.mappings, cur)
+	j := i + dir
+	if i == -1 || j < 0 || j >= len(m.mappings) {
+		return nil
+	}
+
+	a, b := m.mappings[i], m.mappings[j]
+	m.mappings[i], m.mappings[j] = b, a
+	if a.Priority != b.Priority {
+		a.Priority, b.Priority = b.Priority, a.Priority
+		m.dirty[a.ID] = true
+		m.dirty[b.ID] = true
+	}
+
+	// Equal priorities mean the swap did nothing, so bump the upper one (& anything it then runs into)
+	for k := min(i, j); k >= 0 && k+1 < len(m.mappings); k-- {
+		up, down := m.mappings[k], m.mappings[k+1]
+		if up.Priority > down.Priority {
+			break
+		}
+
+		up.Priority = down.Priority + 1
+		m.dirty[up.ID] = true
+	}
+
+	slices.SortStableFunc(m.mappings, m.Compare)
+	m.preview = nil
+
+	return func() tea.Msg { return listeditor.ItemsChanged{} }
+}
+
+func (m *mappingImpl) saveOrder(noRetroactive bool) tea.Cmd {
+	toSave := []*api.Mapping{}
+	for _, v := range m.mappings {
+		if m.dirty[v.ID] {
+			cp := api.Mapping(*v)
+			toSave = append(toSave, &cp)
+		}
+	}
+
+	return m.saveMappings(m.ctx, toSave, noRetroactive)
+}
+
+// Sends every mapping in ms, one by one since there is no batch endpoint
+func (m *mappingImpl) saveMappings(ctx context.Context, ms []*api.Mapping, noRetroactive bool) tea.Cmd {
+	return func() tea.Msg {
+		saved := orderSaved{}
+		for i, v := range ms {
+			err := m.api.MappingsUpdate(ctx, v.ID, v, noRetroactive)
+			if err != nil {
+				// The ones before this are saved, so only those are clean
+				return tea.BatchMsg{
+					func() tea.Msg { return saved },
+					func() tea.Msg { return notify.Error(err, m.saveMappings(ctx, ms[i:], noRetroactive)) },
+				}
+			}
+
+			saved.ids = append(saved.ids, v.ID)
+		}
+
+		return saved
+	}
+}
+
+func (m *mappingImpl) handleOrderKey(msg tea.KeyPressMsg) (tea.Cmd, bool) {
+	switch {
+	case key.Matches(msg, KEY_ORDER_SAVE):
+		m.confirmOpen = false
+		return m.saveOrder(false), true
+	case key.Matches(msg, KEY_ORDER_SAVE_NEW):
+		m.confirmOpen = false
+		return m.saveOrder(true), true
+	case key.Matches(msg, KEY_ORDER_CANCEL):
+		m.confirmOpen = false
+	}
+
+	return nil, true
+}
+
+func (m *mappingImpl) renderOrderConfirm(w int) string {
+	names := []string{}
+	for _, v := range m.mappings {
+		if m.dirty[v.ID] {
+			names = append(names, "  "+utils.Overflow(v.Name, w-8)+styles.S_TEXT_DISABLED.Render(" → "+strconv.Itoa(v.Priority)))
+		}
+	}
+
+	lines := []string{
+		STYLE_PREVIEW_TITLE.Render("Save Priorities"),
+		"",
+		strconv.Itoa(len(names)) + " mappings will be updated:",
+	}
+	lines = append(lines, names...)
+	lines = append(lines, "")
+	for _, k := range []key.Binding{KEY_ORDER_SAVE, KEY_ORDER_SAVE_NEW, KEY_ORDER_CANCEL} {
+		h := k.Help()
+		lines = append(lines, fmt.Sprintf("%-8s %s", h.Key, h.Desc))
+	}
+
+	for i, l := range lines {
+		lines[i] = utils.Overflow(l, w)
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,144 @@
+package mappings
+
+import (
+	"strconv"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+	"github.com/bank_data_tui/api"
+	"github.com/bank_data_tui/styles"
+	"github.com/bank_data_tui/utils"
+	"github.com/bank_data_tui/utils/editor"
+)
+
+const PREVIEW_AMT_WIDTH = 9
+
+var (
+	STYLE_PREVIEW_TITLE  lipgloss.Style
+	STYLE_PREVIEW_SHADOW lipgloss.Style
+)
+
+func init() {
+	styles.OnTheme(func() {
+		STYLE_PREVIEW_TITLE = lipgloss.NewStyle().Bold(true).Foreground(styles.COLOR_MAIN)
+		STYLE_PREVIEW_SHADOW = lipgloss.NewStyle().Foreground(styles.COLOR_WRONG).Faint(true)
+	})
+}
+
+// What the mapping in the editor would match, worked out whenever what's typed into it changes
+type preview struct {
+	// What it was worked out from, the editor's item, regex, amount & priority
+	inputs [4]string
+
+	// Shown in place of the matches when set
+	msg      string
+	matched  []*api.Transaction
+	shadows  []*api.Mapping
+	shadowed int
+}
+
+func (m *mappingImpl) EditorUpdated(e *editor.Model) {
+	inputs := [4]string{e.ItemID, e.FieldValue(FIELD_REGEX), e.FieldValue(FIELD_AMOUNT), e.FieldValue(FIELD_PRIORITY)}
+	if m.preview != nil && m.preview.inputs == inputs {
+		return
+	}
+
+	m.preview = m.makePreview(inputs)
+}
+
+func (m *mappingImpl) makePreview(inputs [4]string) *preview {
+	p := &preview{inputs: inputs}
+	if m.cache.Sample == nil {
+		p.msg = "Loading transactions…"
+		return p
+	}
+
+	draft := &api.Mapping{InpText: inputs[1]}
+	if raw := inputs[2]; raw != "" {
+		amt, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			p.msg = "Amount isn't a number"
+			return p
+		}
+		draft.InpAmt = &amt
+	}
+	if draft.InpText == "" && draft.InpAmt == nil {
+		p.msg = "Type a regex or an amount to see what it matches"
+		return p
+	}
+
+	match, err := draft.Matcher()
+	if err != nil {
+		p.msg = "Regex isn't valid"
+		return p
+	}
+
+	priority, _ := strconv.Atoi(inputs[3])
+	others := m.higherPriority(inputs[0], priority)
+
+	for _, t := range m.cache.Sample {
+		if !match(t) {
+			continue
+		}
+
+		s := api.ResolvingMapping(others, t)
+		if s != nil {
+			p.shadowed++
+		}
+		p.matched = append(p.matched, t)
+		p.shadows = append(p.shadows, s)
+	}
+
+	return p
+}
+
+// Shows what the mapping in the editor would match, before it's saved
+func (m *mappingImpl) EditorFooter(w, h int, e *editor.Model) string {
+	p := m.preview
+	if p == nil {
+		return ""
+	}
+
+	title := STYLE_PREVIEW_TITLE.Render("Preview")
+	if p.msg != "" {
+		return title + "\n" + styles.S_TEXT_DISABLED.Render(utils.Overflow(p.msg, w))
+	}
+
+	summary := "Matches " + strconv.Itoa(len(p.matched)) + " of " + strconv.Itoa(len(m.cache.Sample)) + " recent transactions"
+	if p.shadowed != 0 {
+		summary += STYLE_PREVIEW_SHADOW.Render(" (" + strconv.Itoa(p.shadowed) + " taken by other mappings)")
+	}
+
+	lines := []string{title + " " + utils.Overflow(summary, w-lipgloss.Width(title)-1)}
+	for i, t := range p.matched {
+		if len(lines) == h {
+			break
+		}
+
+		lines = append(lines, m.renderPreviewRow(w, t, p.shadows[i]))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (m *mappingImpl) renderPreviewRow(w int, t *api.Transaction, shadow *api.Mapping) string {
+	date := m.cfg.FormatDate(t.AuthedAt)
+	amt := lipgloss.NewStyle().Width(PREVIEW_AMT_WIDTH).Align(lipgloss.Right).Render(
+		m.cfg.Amount.Format(t.Amount),
+	)
+
+	suffix := ""
+	if shadow != nil {
+		suffix = " " + STYLE_PREVIEW_SHADOW.Render("← "+utils.Overflow(shadow.Name, w/4))
+	}
+
+	descW := w - lipgloss.Width(date) - PREVIEW_AMT_WIDTH - lipgloss.Width(suffix) - 2
+	desc := lipgloss.NewStyle().Width(max(descW, 0)).Render(utils.Overflow(t.Desc, max(descW, 0)))
+
+	row := date + " " + desc + " " + amt + suffix
+	if shadow != nil {
+		row = styles.S_TEXT_DISABLED.Render(date+" "+desc+" "+amt) + suffix
+	}
+
+	return utils.Overflow(row, w)
+}
@@ -0,0 +1,53 @@
+package reports
+
+import (
+	"charm.land/bubbles/v2/key"
+	"github.com/bank_data_tui/utils"
+	"github.com/bank_data_tui/utils/keymap"
+)
+
+var (
+	KEY_UP     = key.NewBinding(key.WithKeys("up"), key.WithHelp("↑", "up"))
+	KEY_DOWN   = key.NewBinding(key.WithKeys("down"), key.WithHelp("↓", "down"))
+	KEY_LEFT   = key.NewBinding(key.WithKeys("left"), key.WithHelp("←", "older"))
+	KEY_RIGHT  = key.NewBinding(key.WithKeys("right"), key.WithHelp("→", "newer"))
+	KEY_PERIOD = key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "week / month / year"))
+	KEY_CHART  = key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "table / chart"))
+	KEY_OPEN   = key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "show the transactions"))
+
+	// While looking at a cell's transactions
+	KEY_BACK = key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "back to the report"))
+)
+
+func init() {
+	keymap.Register("reports", map[string]*key.Binding{
+		"up":     &KEY_UP,
+		"down":   &KEY_DOWN,
+		"left":   &KEY_LEFT,
+		"right":  &KEY_RIGHT,
+		"period": &KEY_PERIOD,
+		"chart":  &KEY_CHART,
+		"open":   &KEY_OPEN,
+	})
+	// Not related to transactions, esc only goes back when the list doesn't need it
+	keymap.Register("reports.drill", map[string]*key.Binding{
+		"back": &KEY_BACK,
+	})
+}
+
+func (m *Model) KeyHelp() []key.Binding {
+	if m.drill != nil {
+		res := keymap.Group("reports.drill")
+		if kh, ok := m.drill.(utils.KeyHelper); ok {
+			res = append(res, kh.KeyHelp()...)
+		}
+		return res
+	}
+
+	return keymap.Group("reports")
+}
+
+func (m *Model) Typing() bool {
+	t, ok := m.drill.(utils.Typer)
+	return ok && t.Typing()
+}
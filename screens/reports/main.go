@@ -0,0 +1,229 @@
+package reports
+
+import (
+	"context"
+	"time"
+
+	"charm.land/bubbles/v2/key"
+	"charm.land/bubbles/v2/spinner"
+	tea "charm.land/bubbletea/v2"
+	"github.com/bank_data_tui/api"
+	"github.com/bank_data_tui/config"
+	"github.com/bank_data_tui/screens/transactions"
+	"github.com/bank_data_tui/styles"
+	"github.com/bank_data_tui/utils"
+	"github.com/bank_data_tui/utils/notify"
+	"github.com/bank_data_tui/utils/repo"
+)
+
+// What the current bucket is worked out from. Swapped out in tests
+var NOW = time.Now
+
+type Model struct {
+	ctx   context.Context
+	api   *api.APIClient
+	cache *repo.Cache
+	cfg   *config.Config
+	w, h  int
+
+	spin spinner.Model
+	// Index in PERIODS
+	period int
+	// nil until the first load. Kept while refreshing, so that it doesn't flash
+	rep *report
+	// Bumped on every fetch, so that a slow old one doesn't overwrite a newer one
+	gen int
+
+	// Selected cell. Columns are buckets, the last one being the current one
+	selRow, selCol int
+	rowOff         int
+	// Stacked bars of the buckets instead of the table
+	chart bool
+
+	// The transactions of a cell, nil when looking at the report
+	drill      utils.Screen
+	drillTitle string
+}
+
+func New(ctx context.Context, api *api.APIClient, cache *repo.Cache, cfg *config.Config, w, h int) *Model {
+	return &Model{
+		ctx:    ctx,
+		api:    api,
+		cache:  cache,
+		cfg:    cfg,
+		w:      w,
+		h:      h,
+		spin:   spinner.New(spinner.WithStyle(styles.S_TEXT_HIGHLIGHT)),
+		period: 1,
+		selCol: PERIODS[1].count - 1,
+	}
+}
+
+type loaded struct {
+	rep *report
+	gen int
+}
+
+func (m *Model) Init() tea.Cmd {
+	return tea.Batch(m.spin.Tick, m.refresh())
+}
+
+// Transactions & categories could've changed on any other screen
+func (m *Model) Focus() tea.Cmd {
+	cmds := []tea.Cmd{m.refresh()}
+	if f, ok := m.drill.(utils.Focuser); ok {
+		cmds = append(cmds, f.Focus())
+	}
+
+	return tea.Batch(cmds...)
+}
+
+func (m *Model) refresh() tea.Cmd {
+	m.gen++
+	return m.fetch(PERIODS[m.period], m.gen)
+}
+
+// Every transaction in p's buckets, & the categories to group them by
+func (m *Model) fetch(p period, gen int) tea.Cmd {
+	return func() tea.Msg {
+		now := NOW()
+		// Only spending is counted
+		amtMax := 0.0
+
+		all := []*api.Transaction{}
+		for page := 1; ; page++ {
+			d, err := m.api.TransactionsFetch(m.ctx, api.TransactionQuery{
+				Page:      page,
+				PageSize:  m.cfg.Transactions.PageSize,
+				From:      p.starts(now)[0],
+				AmountMax: &amtMax,
+			})
+			if err != nil {
+				return notify.Error(err, m.fetch(p, gen))
+			}
+
+			all = append(all, d.Data...)
+			if len(d.Data) == 0 || len(all) >= d.Total {
+				break
+			}
+		}
+
+		if _, err := m.cache.EasyCategories(m.ctx, m.api); err != nil {
+			return notify.Error(err, m.fetch(p, gen))
+		}
+
+		return loaded{rep: build(p, all, m.cache, now), gen: gen}
+	}
+}
+
+// Selects the cell at row, col (clamped into the report), scrolling it into view
+func (m *Model) selectCell(row, col int) {
+	if m.rep == nil {
+		return
+	}
+
+	m.selRow = max(min(row, len(m.rep.rows)-1), 0)
+	m.selCol = max(min(col, m.rep.p.count-1), m.rep.p.count-m.shownCols())
+
+	rows := m.tableRows()
+	if m.selRow < m.rowOff {
+		m.rowOff = m.selRow
+	} else if m.selRow >= m.rowOff+rows {
+		m.rowOff = m.selRow - rows + 1
+	}
+	m.rowOff = max(min(m.rowOff, len(m.rep.rows)-rows), 0)
+}
+
+func (m *Model) openDrill() tea.Cmd {
+	t := transactions.New(m.ctx, m.api, m.cache, m.cfg, m.w, m.h-DRILL_TITLE_HEIGHT)
+	t.SetQuery(m.rep.query(m.selRow, m.selCol))
+
+	m.drill = t
+	m.drillTitle = m.rep.rows[m.selRow].name() + " · " + m.rep.bucketName(m.selCol)
+
+	return m.drill.Init()
+}
+
+func (m *Model) Update(msg tea.Msg) (utils.Screen, tea.Cmd) {
+	switch msg := msg.(type) {
+	case loaded:
+		if msg.gen != m.gen {
+			return m, nil
+		}
+
+		m.rep = msg.rep
+		m.selectCell(m.selRow, m.selCol)
+		return m, nil
+	case utils.ResizeMessage:
+		m.w, m.h = msg.W, msg.H
+		m.selectCell(m.selRow, m.selCol)
+		if m.drill != nil {
+			return m.updateDrill(utils.ResizeMessage{W: msg.W, H: msg.H - DRILL_TITLE_HEIGHT})
+		}
+		return m, nil
+	case tea.KeyPressMsg:
+		if m.drill != nil {
+			// Closing the list's filter or pane comes first
+			busy := false
+			if b, ok := m.drill.(interface{ Busy() bool }); ok {
+				busy = b.Busy()
+			}
+			if key.Matches(msg, KEY_BACK) && !busy {
+				m.drill = nil
+				return m, nil
+			}
+			return m.updateDrill(msg)
+		}
+
+		switch {
+		case key.Matches(msg, KEY_UP):
+			m.selectCell(m.selRow-1, m.selCol)
+		case key.Matches(msg, KEY_DOWN):
+			m.selectCell(m.selRow+1, m.selCol)
+		case key.Matches(msg, KEY_LEFT):
+			m.selectCell(m.selRow, m.selCol-1)
+		case key.Matches(msg, KEY_RIGHT):
+			m.selectCell(m.selRow, m.selCol+1)
+		case key.Matches(msg, KEY_PERIOD):
+			m.period = (m.period + 1) % len(PERIODS)
+			m.selCol = PERIODS[m.period].count - 1
+			cmd := m.refresh()
+			if m.rep != nil {
+				// The spinner stopped once it was loaded
+				m.rep = nil
+				cmd = tea.Batch(cmd, m.spin.Tick)
+			}
+			return m, cmd
+		case key.Matches(msg, KEY_CHART):
+			m.chart = !m.chart
+			// More columns fit in one than in the other
+			m.selectCell(m.selRow, m.selCol)
+		case key.Matches(msg, KEY_OPEN):
+			if m.rep != nil {
+				return m, m.openDrill()
+			}
+		}
+		return m, nil
+	}
+
+	var cmds []tea.Cmd
+	if _, ok := msg.(spinner.TickMsg); ok && m.rep == nil {
+		var cmd tea.Cmd
+		m.spin, cmd = m.spin.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+	// Anything else is for the list's own requests
+	if m.drill != nil {
+		_, cmd := m.updateDrill(msg)
+		cmds = append(cmds, cmd)
+	}
+
+	return m, tea.Batch(cmds...)
+}
+
+func (m *Model) updateDrill(msg tea.Msg) (utils.Screen, tea.Cmd) {
+	var cmd tea.Cmd
+	m.drill, cmd = m.drill.Update(msg)
+
+	return m, cmd
+}
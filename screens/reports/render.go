@@ -0,0 +1,272 @@
+package reports
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/bank_data_tui/styles"
+	"github.com/bank_data_tui/utils"
+	"github.com/bank_data_tui/utils/chart"
+)
+
+const (
+	LABEL_WIDTH = 16
+	// Fits 12,345.67 & a space
+	CELL_WIDTH  = 10
+	DELTA_WIDTH = 8
+	// Between the delta & the trend
+	TREND_GAP = 2
+	// Title & column headings above the rows, an empty line & the status line below them
+	TABLE_CHROME = 4
+	// Title & an empty line above the transactions of a cell
+	DRILL_TITLE_HEIGHT = 2
+)
+
+var (
+	STYLE_TITLE    lipgloss.Style
+	STYLE_SELECTED lipgloss.Style
+)
+
+func init() {
+	styles.OnTheme(func() {
+		STYLE_TITLE = lipgloss.NewStyle().Bold(true).Foreground(styles.COLOR_MAIN)
+		STYLE_SELECTED = lipgloss.NewStyle().Bold(true).Underline(true).Foreground(styles.COLOR_MAIN)
+	})
+}
+
+// How many buckets fit next to the labels, the average, the delta & the trend. The newest ones are shown
+func (m *Model) shownCols() int {
+	if m.chart {
+		return max(chart.Fits(m.w, PERIODS[m.period].count), 1)
+	}
+
+	n := (m.w - LABEL_WIDTH - CELL_WIDTH - DELTA_WIDTH - m.trendWidth()) / CELL_WIDTH
+	return max(min(n, PERIODS[m.period].count), 1)
+}
+
+// A sparkline of every bucket, for when they don't all fit as columns. 0 when there isn't room for it & 2 columns
+func (m *Model) trendWidth() int {
+	count := PERIODS[m.period].count
+	w := TREND_GAP + count
+	rest := m.w - LABEL_WIDTH - CELL_WIDTH - DELTA_WIDTH
+	if rest >= count*CELL_WIDTH || rest-w < 2*CELL_WIDTH {
+		return 0
+	}
+
+	return w
+}
+
+func (m *Model) tableRows() int {
+	return max(m.h-TABLE_CHROME, 1)
+}
+
+func (m *Model) View() (string, *tea.Cursor) {
+	if m.drill != nil {
+		v, cur := m.drill.View()
+		if cur != nil {
+			cur.Y += DRILL_TITLE_HEIGHT
+		}
+
+		title := STYLE_TITLE.Render(utils.Overflow(m.drillTitle, m.w-lipgloss.Width(KEY_BACK.Help().Key)-4))
+		back := styles.S_TEXT_DISABLED.Render(KEY_BACK.Help().Key + " back")
+		return utils.JoinHorizontal2(m.w, title, back) + "\n\n" + v, cur
+	}
+
+	if m.rep == nil {
+		return m.spin.View(), nil
+	}
+
+	lines := []string{m.renderTitle()}
+	if m.chart {
+		lines = append(lines, m.renderChart())
+	} else {
+		lines = append(lines, m.renderColHeader())
+
+		end := min(m.rowOff+m.tableRows(), len(m.rep.rows))
+		for i := m.rowOff; i < end; i++ {
+			lines = append(lines, m.renderRow(i))
+		}
+	}
+
+	box := lipgloss.NewStyle().Width(m.w).Height(m.h - 1).MaxHeight(m.h - 1)
+	return box.Render(strings.Join(lines, "\n")) + "\n" + m.renderStatus(), nil
+}
+
+// With as many of the report's keys as fit
+func (m *Model) renderTitle() string {
+	title := STYLE_TITLE.Render("Spending per " + m.rep.p.name)
+
+	hints := ""
+	for _, k := range []key.Binding{KEY_PERIOD, KEY_CHART} {
+		h := hints
+		if h != "" {
+			h += "  "
+		}
+		h += k.Help().Key + " " + k.Help().Desc
+		if lipgloss.Width(title)+lipgloss.Width(h)+1 > m.w {
+			break
+		}
+		hints = h
+	}
+
+	return utils.JoinHorizontal2(m.w, title, styles.S_TEXT_DISABLED.Render(hints))
+}
+
+func pad(s string, w int) string {
+	return lipgloss.PlaceHorizontal(w, lipgloss.Right, utils.Overflow(s, w-1))
+}
+
+func (m *Model) renderColHeader() string {
+	first := m.rep.p.count - m.shownCols()
+
+	var b strings.Builder
+	b.WriteString(strings.Repeat(" ", LABEL_WIDTH))
+	for i := first; i < m.rep.p.count; i++ {
+		t := pad(m.rep.starts[i].Format(m.rep.p.label), CELL_WIDTH)
+		if i == m.selCol {
+			b.WriteString(STYLE_TITLE.Render(t))
+		} else {
+			b.WriteString(styles.S_TEXT_DISABLED.Render(t))
+		}
+	}
+	b.WriteString(styles.S_TEXT_DISABLED.Render(pad("Avg", CELL_WIDTH) + pad("Δ", DELTA_WIDTH)))
+	if w := m.trendWidth(); w != 0 {
+		b.WriteString(styles.S_TEXT_DISABLED.Render(strings.Repeat(" ", TREND_GAP) + "Trend"))
+	}
+
+	return b.String()
+}
+
+func (m *Model) renderRow(i int) string {
+	r := m.rep.rows[i]
+	first := m.rep.p.count - m.shownCols()
+
+	label := utils.Overflow(r.name(), LABEL_WIDTH-1)
+	base := lipgloss.NewStyle()
+	if r.total {
+		base = base.Bold(true)
+		label = base.Render(label)
+	} else {
+		label = chart.CategoryStyle(r.cat).Render(label)
+	}
+
+	var b strings.Builder
+	b.WriteString(lipgloss.NewStyle().Width(LABEL_WIDTH).Render(label))
+	for c := first; c < m.rep.p.count; c++ {
+		style := base
+		txt := m.cfg.Amount.Format(r.cells[c].spent)
+		if r.cells[c].count == 0 {
+			txt = "-"
+			style = styles.S_TEXT_DISABLED
+		}
+		if i == m.selRow && c == m.selCol {
+			style = STYLE_SELECTED
+		}
+		b.WriteString(lipgloss.PlaceHorizontal(CELL_WIDTH, lipgloss.Right, style.Render(utils.Overflow(txt, CELL_WIDTH-1))))
+	}
+
+	b.WriteString(styles.S_TEXT_DISABLED.Render(pad(m.cfg.Amount.Format(r.avg(m.rep.first)), CELL_WIDTH)))
+	b.WriteString(pad(renderDelta(r, m.selCol), DELTA_WIDTH))
+	if w := m.trendWidth(); w != 0 {
+		vals := make([]float64, len(r.cells))
+		for i, c := range r.cells {
+			vals[i] = c.spent
+		}
+
+		color := chart.CategoryColor(r.cat)
+		if r.total {
+			color = styles.COLOR_MAIN
+		}
+		b.WriteString(strings.Repeat(" ", TREND_GAP) + chart.Sparkline(w-TREND_GAP, vals, color))
+	}
+
+	return b.String()
+}
+
+// Stacked bars of the shown buckets, a segment per category, with what colour is which under them
+func (m *Model) renderChart() string {
+	first := m.rep.p.count - m.shownCols()
+	// The total is what the bars add up to already
+	cats := m.rep.rows[:len(m.rep.rows)-1]
+
+	cols := []chart.Column{}
+	for c := first; c < m.rep.p.count; c++ {
+		label := styles.S_TEXT_DISABLED.Render(m.rep.starts[c].Format(m.rep.p.label))
+		if c == m.selCol {
+			label = STYLE_TITLE.Render(m.rep.starts[c].Format(m.rep.p.label))
+		}
+
+		col := chart.Column{Label: label}
+		// Biggest at the bottom
+		for _, r := range slices.Backward(cats) {
+			col.Segments = append(col.Segments, chart.Segment{Value: r.cells[c].spent, Color: chart.CategoryColor(r.cat)})
+		}
+		cols = append(cols, col)
+	}
+
+	legend := m.renderLegend(cats)
+	// The title & a blank line above the legend, the status line below
+	h := m.h - lipgloss.Height(legend) - 3
+
+	return chart.Stacked(m.w, h, cols) + "\n\n" + legend
+}
+
+func (m *Model) renderLegend(cats []reportRow) string {
+	lines := []string{""}
+	for i, r := range cats {
+		name := r.name()
+		if i == m.selRow {
+			name = lipgloss.NewStyle().Underline(true).Render(name)
+		}
+		item := chart.CategoryStyle(r.cat).Render("■ ") + name
+
+		last := &lines[len(lines)-1]
+		switch {
+		case *last == "":
+			*last = item
+		case lipgloss.Width(*last)+2+lipgloss.Width(item) <= m.w:
+			*last += "  " + item
+		default:
+			lines = append(lines, item)
+		}
+	}
+
+	for i, l := range lines {
+		lines[i] = utils.Overflow(l, m.w)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Change of col against the bucket before it. Spending more is bad
+func renderDelta(r reportRow, col int) string {
+	d, ok := r.delta(col)
+	switch {
+	case !ok:
+		return ""
+	case d >= 0.5:
+		return styles.S_TEXT_WRONG.Render(fmt.Sprintf("▲ %.0f%%", d))
+	case d <= -0.5:
+		return styles.S_TEXT_HIGHLIGHT_SECONDARY.Render(fmt.Sprintf("▼ %.0f%%", -d))
+	}
+
+	return styles.S_TEXT_DISABLED.Render("same")
+}
+
+func (m *Model) renderStatus() string {
+	r := m.rep.rows[m.selRow]
+	c := r.cells[m.selCol]
+
+	txt := r.name() + ", " + m.rep.bucketName(m.selCol) + ": " + m.cfg.Amount.Format(c.spent) +
+		" over " + strconv.Itoa(c.count) + " transactions"
+	if c.count != 0 {
+		txt += " · " + KEY_OPEN.Help().Key + " to see them"
+	}
+
+	return styles.S_TEXT_DISABLED.Render(utils.Overflow(txt, m.w))
+}
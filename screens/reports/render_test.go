@@ -0,0 +1,73 @@
+package reports
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bank_data_tui/api"
+	"github.com/bank_data_tui/config"
+	"github.com/bank_data_tui/utils"
+	"github.com/bank_data_tui/utils/repo"
+	"github.com/bank_data_tui/utils/screentest"
+)
+
+func init() {
+	NOW = screentest.Now
+}
+
+func newScreen(c *api.APIClient, w, h int) utils.Screen {
+	return New(context.Background(), c, &repo.Cache{}, config.Default(), w, h)
+}
+
+func TestView(t *testing.T) {
+	screentest.Sizes(t, screentest.SIZES, func(t *testing.T, w, h int) {
+		sh := screentest.ScreenHarness(t, w, h, newScreen)
+		sh.Golden(t.Name())
+
+		sh.Keys("down", "left")
+		sh.Golden(t.Name() + "_moved")
+
+		sh.Keys("p").Settle()
+		sh.Golden(t.Name() + "_year")
+
+		sh.Keys("p").Settle()
+		sh.Golden(t.Name() + "_week")
+	})
+}
+
+func TestViewChart(t *testing.T) {
+	screentest.Sizes(t, screentest.SIZES, func(t *testing.T, w, h int) {
+		sh := screentest.ScreenHarness(t, w, h, newScreen)
+		sh.Keys("c", "down", "left")
+		sh.Golden(t.Name())
+
+		sh.Keys("p", "p").Settle()
+		sh.Golden(t.Name() + "_week")
+	})
+}
+
+// Into a cell's transactions, through the list's own filter & back out
+func TestViewDrill(t *testing.T) {
+	screentest.Sizes(t, screentest.SIZES, func(t *testing.T, w, h int) {
+		sh := screentest.ScreenHarness(t, w, h, newScreen)
+
+		sh.Keys("left", "enter").Settle()
+		sh.Golden(t.Name())
+
+		sh.Keys("/")
+		sh.Keys("esc")
+		sh.Golden(t.Name() + "_filter_closed")
+
+		sh.Keys("esc")
+		sh.Golden(t.Name() + "_back")
+	})
+}
+
+func TestViewResize(t *testing.T) {
+	sh := screentest.ScreenHarness(t, 120, 35, newScreen)
+	sh.Keys("down", "down", "left", "left")
+
+	sh.Send(utils.ResizeMessage{W: 50, H: 15})
+	sh.Golden(t.Name())
+	sh.GoldenANSI(t.Name())
+}
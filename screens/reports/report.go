@@ -0,0 +1,231 @@
+package reports
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bank_data_tui/api"
+	"github.com/bank_data_tui/screens/transactions"
+	"github.com/bank_data_tui/utils/repo"
+)
+
+// How transactions are bucketed
+type period struct {
+	name string
+	// How many buckets are fetched, the newest being the current one
+	count int
+	// Start of the bucket t is in
+	start func(t time.Time) time.Time
+	// Start of the bucket after the one starting at t
+	next func(t time.Time) time.Time
+	// Go time layout of the column headings
+	label string
+	// Name of the bucket starting at t, for the title of its transactions
+	title func(t time.Time) string
+}
+
+var PERIODS = []period{
+	{
+		name:  "week",
+		count: 12,
+		start: func(t time.Time) time.Time {
+			// Weeks start on Monday
+			d := (int(t.Weekday()) + 6) % 7
+			return time.Date(t.Year(), t.Month(), t.Day()-d, 0, 0, 0, 0, t.Location())
+		},
+		next:  func(t time.Time) time.Time { return t.AddDate(0, 0, 7) },
+		label: "02 Jan",
+		title: func(t time.Time) string { return "week of " + t.Format("2 Jan 2006") },
+	},
+	{
+		name:  "month",
+		count: 12,
+		start: func(t time.Time) time.Time {
+			return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+		},
+		next:  func(t time.Time) time.Time { return t.AddDate(0, 1, 0) },
+		label: "Jan 06",
+		title: func(t time.Time) string { return t.Format("January 2006") },
+	},
+	{
+		name:  "year",
+		count: 5,
+		start: func(t time.Time) time.Time {
+			return time.Date(t.Year(), 1, 1, 0, 0, 0, 0, t.Location())
+		},
+		next:  func(t time.Time) time.Time { return t.AddDate(1, 0, 0) },
+		label: "2006",
+		title: func(t time.Time) string { return t.Format("2006") },
+	},
+}
+
+// Where the buckets of p up to now start, oldest first. The one after the last is where the current one ends
+func (p period) starts(now time.Time) []time.Time {
+	res := make([]time.Time, p.count+1)
+	res[p.count-1] = p.start(now)
+	for i := p.count - 2; i >= 0; i-- {
+		// A day before the start is always in the previous bucket
+		res[i] = p.start(res[i+1].AddDate(0, 0, -1))
+	}
+	res[p.count] = p.next(res[p.count-1])
+
+	return res
+}
+
+type cell struct {
+	// Money going out, as a positive number. Income isn't counted
+	spent float64
+	count int
+}
+
+type reportRow struct {
+	// nil for the uncategorised row & the total
+	cat   *api.Category
+	cells []cell
+	total bool
+}
+
+func (r reportRow) name() string {
+	switch {
+	case r.total:
+		return "Total"
+	case r.cat == nil:
+		return "Uncategorised"
+	}
+
+	return r.cat.Icon + " " + r.cat.Name
+}
+
+// Average of the buckets from first on, but not the current one as it's still going. 0 when there's none
+func (r reportRow) avg(first int) float64 {
+	done := r.cells[first : len(r.cells)-1]
+	if len(done) == 0 {
+		return 0
+	}
+
+	return sum(done) / float64(len(done))
+}
+
+// Change from the bucket before i to i, in %. ok is false when there's nothing to compare to
+func (r reportRow) delta(i int) (float64, bool) {
+	if i == 0 || r.cells[i-1].spent == 0 {
+		return 0, false
+	}
+
+	prev := r.cells[i-1].spent
+	return (r.cells[i].spent - prev) / prev * 100, true
+}
+
+type report struct {
+	p      period
+	starts []time.Time
+	// First bucket with any spending, the ones before are left out of averages (ie. before the account was opened)
+	first int
+	// Categories with any spending, biggest first, then uncategorised (if any) & the total
+	rows []reportRow
+}
+
+func build(p period, ts []*api.Transaction, cache *repo.Cache, now time.Time) *report {
+	r := &report{p: p, starts: p.starts(now)}
+
+	perCat := map[*api.Category][]cell{}
+	uncat := make([]cell, p.count)
+	total := make([]cell, p.count)
+
+	for _, t := range ts {
+		if t.Amount >= 0 {
+			continue
+		}
+
+		i, found := slices.BinarySearchFunc(r.starts, t.AuthedAt, func(s, at time.Time) int { return s.Compare(at) })
+		if !found {
+			// Index of the bucket it's in, rather than of where it would be inserted
+			i--
+		}
+		if i < 0 || i >= p.count {
+			continue
+		}
+
+		var cat *api.Category
+		if t.ResolvedCategoryID != nil {
+			cat = cache.Category(*t.ResolvedCategoryID)
+		}
+
+		cells := uncat
+		if cat != nil {
+			if perCat[cat] == nil {
+				perCat[cat] = make([]cell, p.count)
+			}
+			cells = perCat[cat]
+		}
+
+		for _, c := range [][]cell{cells, total} {
+			c[i].spent -= t.Amount
+			c[i].count++
+		}
+	}
+
+	for c, cells := range perCat {
+		r.rows = append(r.rows, reportRow{cat: c, cells: cells})
+	}
+	slices.SortFunc(r.rows, func(a, b reportRow) int {
+		if c := cmp.Compare(sum(b.cells), sum(a.cells)); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.cat.Name, b.cat.Name)
+	})
+
+	if sum(uncat) != 0 {
+		r.rows = append(r.rows, reportRow{cells: uncat})
+	}
+	r.rows = append(r.rows, reportRow{cells: total, total: true})
+
+	r.first = slices.IndexFunc(total, func(c cell) bool { return c.count != 0 })
+	if r.first == -1 {
+		r.first = p.count - 1
+	}
+
+	return r
+}
+
+func sum(cells []cell) float64 {
+	s := 0.0
+	for _, c := range cells {
+		s += c.spent
+	}
+
+	return s
+}
+
+func (r *report) bucketName(col int) string {
+	return r.p.title(r.starts[col])
+}
+
+// The transactions with the spending of one cell, along with how the filter bar shows that
+func (r *report) query(row, col int) (api.TransactionQuery, string) {
+	// Only money going out, like the cells
+	maxAmt := -0.01
+	q := api.TransactionQuery{From: r.starts[col], To: r.starts[col+1], AmountMax: &maxAmt}
+
+	f := []string{
+		"from:" + q.From.Format(transactions.FILTER_DATE_FORMAT),
+		// to: is inclusive
+		"to:" + q.To.AddDate(0, 0, -1).Format(transactions.FILTER_DATE_FORMAT),
+		"max:" + strconv.FormatFloat(maxAmt, 'f', -1, 64),
+	}
+
+	switch rr := r.rows[row]; {
+	case rr.total:
+	case rr.cat == nil:
+		q.CategoryID = api.CATEGORY_NONE
+		f = append(f, "cat:"+api.CATEGORY_NONE)
+	default:
+		q.CategoryID = rr.cat.ID
+		f = append(f, `cat:"`+rr.cat.Name+`"`)
+	}
+
+	return q, strings.Join(f, " ")
+}
@@ -0,0 +1,202 @@
+package reports
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bank_data_tui/api"
+	"github.com/bank_data_tui/utils/repo"
+)
+
+var testCache = &repo.Cache{Categories: []*api.Category{
+	{ID: "1", SavableCategory: api.SavableCategory{Name: "Groceries"}},
+	{ID: "2", SavableCategory: api.SavableCategory{Name: "Bills"}},
+}}
+
+func spend(at time.Time, amount float64, cat string) *api.Transaction {
+	t := &api.Transaction{AuthedAt: at, Amount: amount}
+	if cat != "" {
+		t.ResolvedCategoryID = &cat
+	}
+
+	return t
+}
+
+func TestBuildBuckets(t *testing.T) {
+	month := PERIODS[1]
+	now := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+	// Buckets are Apr 2023 to Mar 2024
+	first := time.Date(2023, 4, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, c := range []struct {
+		name string
+		at   time.Time
+		// Bucket it lands in, -1 for none
+		want int
+	}{
+		{"start of the first", first, 0},
+		{"just before the first", first.Add(-time.Nanosecond), -1},
+		{"start of a month", time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC), 2},
+		{"end of a month", time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC).Add(-time.Nanosecond), 1},
+		{"current month", now, 11},
+		{"end of the current month", end.Add(-time.Nanosecond), 11},
+		{"after the current month", end, -1},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			r := build(month, []*api.Transaction{spend(c.at, -10, "1")}, testCache, now)
+			total := r.rows[len(r.rows)-1]
+
+			for i, cl := range total.cells {
+				want := cell{}
+				if i == c.want {
+					want = cell{spent: 10, count: 1}
+				}
+				if cl != want {
+					t.Errorf("bucket %d = %+v, want %+v", i, cl, want)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildRows(t *testing.T) {
+	now := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+	r := build(PERIODS[1], []*api.Transaction{
+		spend(now, -5, "1"),
+		spend(now, -20, "2"),
+		spend(now, -1, ""),
+		spend(now, -2, "deleted"),
+		// Income isn't spending
+		spend(now, 100, "1"),
+	}, testCache, now)
+
+	want := []struct {
+		name  string
+		spent float64
+	}{{"Bills", 20}, {"Groceries", 5}, {"Uncategorised", 3}, {"Total", 28}}
+	if len(r.rows) != len(want) {
+		t.Fatalf("got %d rows, want %d", len(r.rows), len(want))
+	}
+	for i, w := range want {
+		rr := r.rows[i]
+		name := "Total"
+		if !rr.total {
+			name = "Uncategorised"
+			if rr.cat != nil {
+				name = rr.cat.Name
+			}
+		}
+		if name != w.name || sum(rr.cells) != w.spent {
+			t.Errorf("rows[%d] = %s %v, want %s %v", i, name, sum(rr.cells), w.name, w.spent)
+		}
+	}
+}
+
+func TestBuildFirst(t *testing.T) {
+	now := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+
+	for _, c := range []struct {
+		name string
+		ts   []*api.Transaction
+		want int
+	}{
+		{"nothing", nil, 11},
+		{"only income", []*api.Transaction{spend(time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC), 10, "1")}, 11},
+		{"oldest spending", []*api.Transaction{
+			spend(time.Date(2023, 9, 10, 0, 0, 0, 0, time.UTC), -10, "1"),
+			spend(time.Date(2023, 7, 10, 0, 0, 0, 0, time.UTC), -10, ""),
+		}, 3},
+		{"from the start", []*api.Transaction{spend(time.Date(2023, 4, 1, 0, 0, 0, 0, time.UTC), -10, "1")}, 0},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			if r := build(PERIODS[1], c.ts, testCache, now); r.first != c.want {
+				t.Errorf("first = %d, want %d", r.first, c.want)
+			}
+		})
+	}
+}
+
+func cells(spent ...float64) reportRow {
+	r := reportRow{}
+	for _, s := range spent {
+		r.cells = append(r.cells, cell{spent: s})
+	}
+
+	return r
+}
+
+func TestAvg(t *testing.T) {
+	for _, c := range []struct {
+		name  string
+		row   reportRow
+		first int
+		want  float64
+	}{
+		{"leaves out the current one", cells(10, 20, 1000), 0, 15},
+		{"from first on", cells(1000, 10, 20, 0), 1, 15},
+		{"empty buckets count", cells(0, 30, 0), 0, 15},
+		{"only the current one", cells(10, 20), 1, 0},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			if got := c.row.avg(c.first); got != c.want {
+				t.Errorf("avg(%d) = %v, want %v", c.first, got, c.want)
+			}
+		})
+	}
+}
+
+func TestDelta(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		row  reportRow
+		i    int
+		want float64
+		ok   bool
+	}{
+		{"up", cells(10, 15), 1, 50, true},
+		{"down", cells(10, 5), 1, -50, true},
+		{"to nothing", cells(10, 0), 1, -100, true},
+		{"from nothing", cells(0, 10), 1, 0, false},
+		{"first bucket", cells(10, 10), 0, 0, false},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			got, ok := c.row.delta(c.i)
+			if got != c.want || ok != c.ok {
+				t.Errorf("delta(%d) = %v, %v, want %v, %v", c.i, got, ok, c.want, c.ok)
+			}
+		})
+	}
+}
+
+func TestQuery(t *testing.T) {
+	now := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+	r := build(PERIODS[1], []*api.Transaction{spend(now, -5, "1"), spend(now, -1, "")}, testCache, now)
+
+	for _, c := range []struct {
+		name    string
+		row     int
+		wantCat string
+		display string
+	}{
+		{"category", 0, "1", `from:2024-03-01 to:2024-03-31 max:-0.01 cat:"Groceries"`},
+		{"uncategorised", 1, api.CATEGORY_NONE, "from:2024-03-01 to:2024-03-31 max:-0.01 cat:none"},
+		{"total", 2, "", "from:2024-03-01 to:2024-03-31 max:-0.01"},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			q, display := r.query(c.row, 11)
+			if q.CategoryID != c.wantCat {
+				t.Errorf("category = %q, want %q", q.CategoryID, c.wantCat)
+			}
+			if !q.From.Equal(r.starts[11]) || !q.To.Equal(r.starts[12]) {
+				t.Errorf("from %v to %v, want the bucket %v to %v", q.From, q.To, r.starts[11], r.starts[12])
+			}
+			if q.AmountMax == nil || *q.AmountMax >= 0 {
+				t.Errorf("max = %v, want only spending", q.AmountMax)
+			}
+			if display != c.display {
+				t.Errorf("display = %q, want %q", display, c.display)
+			}
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package transactions
+
+import (
+	"strconv"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/bank_data_tui/api"
+	"github.com/bank_data_tui/styles"
+	"github.com/bank_data_tui/utils"
+	"github.com/bank_data_tui/utils/listeditor"
+	"github.com/bank_data_tui/utils/notify"
+)
+
+const (
+	WIDTH_DETAIL = 36
+	// The mapping editor has 2 fields per row, so it needs more space
+	WIDTH_MAPPING = 56
+	// Less than this & the detail pane takes over the whole screen
+	WIDTH_LIST_MIN = 40
+)
+
+// Added on to the configured date format
+const DETAIL_TIME_FORMAT = " 15:04"
+
+type mappingsLoaded struct{}
+
+func (m Model) fetchMappings() tea.Cmd {
+	return func() tea.Msg {
+		_, err := m.cache.EasyMappings(m.ctx, m.api)
+		if err != nil {
+			return notify.Error(err, m.fetchMappings())
+		}
+
+		return mappingsLoaded{}
+	}
+}
+
+// Width taken by the split between the list & the pane
+func splitW() int {
+	return lipgloss.Width(listeditor.STYLE_SPLIT.Render(""))
+}
+
+// Preferred width of the content of the side pane
+func (m Model) paneContentW() int {
+	if m.pane == PANE_MAPPING {
+		return WIDTH_MAPPING
+	}
+
+	return WIDTH_DETAIL
+}
+
+// Width of the list part of the screen, 0 when the side pane covers everything
+func (m Model) listW() int {
+	if m.pane == PANE_NONE {
+		return m.w
+	}
+	if w := m.w - m.paneContentW() - splitW(); w >= WIDTH_LIST_MIN {
+		return w
+	}
+
+	return 0
+}
+
+// Actual width of the content of the side pane
+func (m Model) paneW() int {
+	if m.listW() == 0 {
+		return m.w
+	}
+
+	return m.paneContentW()
+}
+
+func (m *Model) setDetailOpen(open bool) tea.Cmd {
+	m.pane = PANE_NONE
+	if open {
+		m.pane = PANE_DETAIL
+	}
+	m.filter.SetWidth(m.filterWidth())
+
+	if open && m.cache.Mappings == nil {
+		return m.fetchMappings()
+	}
+
+	return nil
+}
+
+func (m Model) selectedTransaction() *api.Transaction {
+	if m.selected < 0 || m.selected >= len(m.items) {
+		return nil
+	}
+
+	return m.items[m.selected]
+}
+
+func detailField(w int, title, value string) string {
+	return lipgloss.NewStyle().Faint(true).Foreground(styles.COLOR_MAIN).Render(title) + "\n" +
+		lipgloss.NewStyle().Width(w).Render(value)
+}
+
+func (m Model) mappingDesc(t *api.Transaction) string {
+	if t.Overridden {
+		return styles.S_TEXT_HIGHLIGHT_SECONDARY.Render("Manual override")
+	}
+	if m.cache.Mappings == nil {
+		return styles.S_TEXT_DISABLED.Render("Loading…")
+	}
+
+	mapping := api.ResolvingMapping(m.cache.Mappings, t)
+	if mapping == nil {
+		if t.ResolvedName == nil && t.ResolvedCategoryID == nil {
+			return styles.S_TEXT_DISABLED.Render("None")
+		}
+
+		return styles.S_TEXT_DISABLED.Render("Unknown, no current mapping matches")
+	}
+
+	return mapping.Name + styles.S_TEXT_DISABLED.Render(" (priority "+strconv.Itoa(mapping.Priority)+")")
+}
+
+func (m Model) renderDetail(w, h int) string {
+	t := m.selectedTransaction()
+	if t == nil {
+		return lipgloss.Place(w, h, lipgloss.Center, lipgloss.Center, styles.S_TEXT_DISABLED.Render("Nothing selected"))
+	}
+
+	none := styles.S_TEXT_DISABLED.Render("None")
+
+	name := none
+	if t.ResolvedName != nil {
+		name = *t.ResolvedName
+	}
+
+	category := none
+	if t.ResolvedCategoryID != nil {
+		if cat := m.cache.Category(*t.ResolvedCategoryID); cat != nil {
+			category = "[" + cat.Icon + "] " + cat.Name
+			if validHexColor(cat.Color) {
+				category = lipgloss.NewStyle().Foreground(lipgloss.Color("#" + cat.Color)).Render(category)
+			}
+		} else {
+			category = *t.ResolvedCategoryID
+		}
+	}
+
+	settled := styles.S_TEXT_DISABLED.Render("Not yet")
+	if !t.SettledAt.IsZero() {
+		settled = t.SettledAt.Local().Format(m.cfg.DateFormat + DETAIL_TIME_FORMAT)
+	}
+
+	fields := []string{
+		detailField(w, "ID", t.ID),
+		detailField(w, "Amount", m.cfg.Amount.Format(t.Amount)),
+		detailField(w, "Authed", t.AuthedAt.Local().Format(m.cfg.DateFormat+DETAIL_TIME_FORMAT)),
+		detailField(w, "Settled", settled),
+		detailField(w, "Description", t.Desc),
+		detailField(w, "Resolved Name", name),
+		detailField(w, "Category", category),
+		detailField(w, "Mapping", m.mappingDesc(t)),
+	}
+
+	res := strings.Join(fields, "\n\n")
+
+	// Cut from the bottom rather than overflowing the screen
+	lines := strings.Split(res, "\n")
+	if len(lines) > h {
+		lines = lines[:h]
+	}
+
+	return utils.Overflow(strings.Join(lines, "\n"), w)
+}
+
+func validHexColor(s string) bool {
+	if len(s) != 6 {
+		return false
+	}
+	_, err := strconv.ParseUint(s, 16, 32)
+
+	return err == nil
+}
+
+func (m Model) renderPane() (string, *tea.Cursor) {
+	switch m.pane {
+	case PANE_OVERRIDE:
+		return m.renderEditor("Override", "Delete reverts to the mappings", m.paneW())
+	case PANE_MAPPING:
+		return m.renderEditor("New Mapping", "alt+enter saves without touching old transactions", m.paneW())
+	}
+
+	return m.renderDetail(m.paneW(), m.h), nil
+}
+
+func (m Model) View() (string, *tea.Cursor) {
+	if m.pane == PANE_NONE || m.h == 0 {
+		return m.viewList()
+	}
+
+	pane, paneCur := m.renderPane()
+
+	lw := m.listW()
+	if lw == 0 {
+		return lipgloss.NewStyle().Width(m.w).Height(m.h).Render(pane), paneCur
+	}
+
+	l, cur := m.viewList()
+	if paneCur != nil {
+		paneCur.X += lw + splitW()
+		cur = paneCur
+	}
+
+	return lipgloss.JoinHorizontal(
+		lipgloss.Top,
+		lipgloss.NewStyle().Width(lw).Render(l),
+		listeditor.STYLE_SPLIT.Height(m.h).Render(pane),
+	), cur
+}
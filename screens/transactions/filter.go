@@ -0,0 +1,242 @@
+package transactions
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+	"github.com/bank_data_tui/api"
+	"github.com/bank_data_tui/utils"
+	"github.com/bank_data_tui/utils/notify"
+)
+
+const FILTER_DATE_FORMAT = "2006-01-02"
+
+// Shown as the placeholder of the filter bar
+const FILTER_HELP = "from: to: date:auth|settle min: max: cat: re: name: or just text"
+
+// Every key the filter bar knows, by the name it's handled as
+var FILTER_KEYS = map[string]string{
+	"from": "from", "to": "to", "date": "date", "min": "min", "max": "max", "desc": "desc", "name": "name",
+	"cat": "cat", "category": "cat",
+	"re": "re", "regex": "re",
+}
+
+// cat: was given before the categories were loaded, applying has to wait for them
+var ErrNoCategories = errors.New("cat: waiting for the categories to load")
+
+// Splits on spaces, while keeping "quoted strings" together
+func tokenizeFilter(s string) ([]string, error) {
+	tokens := []string{}
+	cur := &strings.Builder{}
+	inQuote := false
+	started := false
+
+	for _, r := range s {
+		switch {
+		case r == '"':
+			inQuote = !inQuote
+			started = true
+		case r == ' ' && !inQuote:
+			if started {
+				tokens = append(tokens, cur.String())
+				cur.Reset()
+				started = false
+			}
+		default:
+			cur.WriteRune(r)
+			started = true
+		}
+	}
+
+	if inQuote {
+		return nil, fmt.Errorf("Unclosed quote")
+	}
+	if started {
+		tokens = append(tokens, cur.String())
+	}
+
+	return tokens, nil
+}
+
+func parseFilterDate(key, v string) (time.Time, error) {
+	t, err := time.ParseInLocation(FILTER_DATE_FORMAT, v, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s: needs a YYYY-MM-DD date", key)
+	}
+
+	return t, nil
+}
+
+func parseFilterAmount(key, v string) (*float64, error) {
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%s: needs a number", key)
+	}
+
+	return &f, nil
+}
+
+// Parses the filter bar into a query (without ordering/paging).
+// Format is space separated `key:value` pairs, anything without a key is searched for in the description.
+// Every key but desc can only be given once. cats is nil when they aren't loaded yet, which fails with
+// ErrNoCategories if they're needed
+func parseFilter(s string, cats []*api.Category) (api.TransactionQuery, error) {
+	q := api.TransactionQuery{}
+
+	tokens, err := tokenizeFilter(s)
+	if err != nil {
+		return q, err
+	}
+
+	desc := []string{}
+	seen := map[string]bool{}
+
+	for _, t := range tokens {
+		key, v, ok := strings.Cut(t, ":")
+		if !ok {
+			desc = append(desc, t)
+			continue
+		}
+
+		name, known := FILTER_KEYS[strings.ToLower(key)]
+		if known && v == "" {
+			return q, fmt.Errorf("%s: needs a value", key)
+		}
+		if known && name != "desc" {
+			if seen[name] {
+				return q, fmt.Errorf("%s: given more than once", key)
+			}
+			seen[name] = true
+		}
+
+		switch name {
+		case "from":
+			q.From, err = parseFilterDate(key, v)
+		case "to":
+			var to time.Time
+			to, err = parseFilterDate(key, v)
+			// to is inclusive for the user, but exclusive for the api
+			q.To = to.AddDate(0, 0, 1)
+		case "date":
+			switch strings.ToLower(v) {
+			case "auth", "authed":
+				q.DateField = api.TOR_AUTH
+			case "settle", "settled":
+				q.DateField = api.TOR_SETTLE
+			default:
+				err = fmt.Errorf("%s: must be auth or settle", key)
+			}
+		case "min":
+			q.AmountMin, err = parseFilterAmount(key, v)
+		case "max":
+			q.AmountMax, err = parseFilterAmount(key, v)
+		case "cat":
+			if strings.EqualFold(v, api.CATEGORY_NONE) {
+				q.CategoryID = api.CATEGORY_NONE
+				break
+			}
+			if cats == nil {
+				err = ErrNoCategories
+				break
+			}
+
+			i := slices.IndexFunc(cats, func(c *api.Category) bool { return strings.EqualFold(c.Name, v) })
+			if i == -1 {
+				err = fmt.Errorf("%s: unknown category '%s'", key, v)
+			} else {
+				q.CategoryID = cats[i].ID
+			}
+		case "desc":
+			desc = append(desc, v)
+		case "re":
+			if _, rerr := regexp.CompilePOSIX(v); rerr != nil {
+				err = fmt.Errorf("%s: must be a valid (posix) regex", key)
+			}
+			q.DescRegex = v
+		case "name":
+			q.ResolvedName = v
+		default:
+			// Not a key we know, so probably just part of a description
+			desc = append(desc, t)
+		}
+
+		if err != nil {
+			return q, err
+		}
+	}
+
+	if q.AmountMin != nil && q.AmountMax != nil && *q.AmountMin > *q.AmountMax {
+		return q, fmt.Errorf("min is bigger than max")
+	}
+	if !q.From.IsZero() && !q.To.IsZero() && !q.From.Before(q.To) {
+		return q, fmt.Errorf("from is after to")
+	}
+
+	q.DescContains = strings.Join(desc, " ")
+
+	return q, nil
+}
+
+func (m Model) updateFilter(msg tea.KeyPressMsg) (utils.Screen, tea.Cmd) {
+	switch {
+	case key.Matches(msg, KEY_FILTER_APPLY):
+		return m.applyFilter()
+	case key.Matches(msg, KEY_FILTER_CANCEL):
+		m.filterErr = nil
+		m.filter.Blur()
+		m.filter.SetValue(m.appliedFilter)
+
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.filter, cmd = m.filter.Update(msg)
+
+	return m, cmd
+}
+
+// Applies what's in the filter bar, or shows why it can't be
+func (m Model) applyFilter() (utils.Screen, tea.Cmd) {
+	q, err := parseFilter(m.filter.Value(), m.cache.Categories)
+	if errors.Is(err, ErrNoCategories) {
+		m.filterErr = err
+		return m, m.applyFilterLater(m.filter.Value())
+	} else if err != nil {
+		m.filterErr = err
+		return m, nil
+	}
+
+	m.filterErr = nil
+	m.filter.Blur()
+	m.query = q
+	m.appliedFilter = m.filter.Value()
+
+	return m, m.resetPages()
+}
+
+// Sent once the categories are in, to apply a filter that needed them
+type filterCategoriesLoaded string
+
+func (m Model) applyFilterLater(s string) tea.Cmd {
+	return func() tea.Msg {
+		if _, err := m.cache.EasyCategories(m.ctx, m.api); err != nil {
+			return notify.Error(err, m.applyFilterLater(s))
+		}
+
+		return filterCategoriesLoaded(s)
+	}
+}
+
+// Opens the list already filtered by q, with display shown in the filter bar as what's applied
+func (m *Model) SetQuery(q api.TransactionQuery, display string) {
+	m.query = q
+	m.appliedFilter = display
+	m.filter.SetValue(display)
+}
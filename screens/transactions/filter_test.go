@@ -0,0 +1,48 @@
+package transactions
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bank_data_tui/api"
+)
+
+func TestParseFilter(t *testing.T) {
+	cats := []*api.Category{
+		{ID: "1", SavableCategory: api.SavableCategory{Name: "Groceries"}},
+		{ID: "2", SavableCategory: api.SavableCategory{Name: "Eating out"}},
+	}
+
+	for _, c := range []struct {
+		filter string
+		cats   []*api.Category
+		cat    string
+		err    bool
+	}{
+		{filter: "cat:groceries", cats: cats, cat: "1"},
+		{filter: `category:"eating out"`, cats: cats, cat: "2"},
+		{filter: "cat:none", cats: cats, cat: api.CATEGORY_NONE},
+		{filter: "cat:nope", cats: cats, err: true},
+		{filter: "cat:groceries cat:nope", cats: cats, err: true},
+		{filter: "cat:groceries category:groceries", cats: cats, err: true},
+		{filter: "min:1 min:2", cats: cats, err: true},
+		{filter: "re:a regex:b", cats: cats, err: true},
+		{filter: "desc:a desc:b", cats: cats},
+		// Doesn't need them
+		{filter: "cat:none", cat: api.CATEGORY_NONE},
+	} {
+		t.Run(c.filter, func(t *testing.T) {
+			q, err := parseFilter(c.filter, c.cats)
+			if (err != nil) != c.err {
+				t.Fatalf("err = %v", err)
+			}
+			if err == nil && q.CategoryID != c.cat {
+				t.Errorf("category = %q, want %q", q.CategoryID, c.cat)
+			}
+		})
+	}
+
+	if _, err := parseFilter("cat:groceries", nil); !errors.Is(err, ErrNoCategories) {
+		t.Errorf("before the categories load, err = %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+package transactions
+
+import (
+	"charm.land/bubbles/v2/key"
+	"github.com/bank_data_tui/utils/keymap"
+)
+
+var (
+	KEY_UP          = key.NewBinding(key.WithKeys("up"), key.WithHelp("↑", "up"))
+	KEY_DOWN        = key.NewBinding(key.WithKeys("down"), key.WithHelp("↓", "down"))
+	KEY_START       = key.NewBinding(key.WithKeys("home"), key.WithHelp("home", "first transaction"))
+	KEY_END         = key.NewBinding(key.WithKeys("end"), key.WithHelp("end", "last loaded transaction"))
+	KEY_SCROLL_UP   = key.NewBinding(key.WithKeys("alt+up"), key.WithHelp("alt+↑", "scroll up"))
+	KEY_SCROLL_DOWN = key.NewBinding(key.WithKeys("alt+down"), key.WithHelp("alt+↓", "scroll down"))
+
+	KEY_FILTER    = key.NewBinding(key.WithKeys("/"), key.WithHelp("/", "filter"))
+	KEY_SORT_NEXT = key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "next sort field"))
+	KEY_SORT_PREV = key.NewBinding(key.WithKeys("S"), key.WithHelp("S", "previous sort field"))
+	KEY_SORT_DIR  = key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "reverse sort"))
+
+	KEY_DETAIL             = key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "toggle details"))
+	KEY_CLOSE              = key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "close details"))
+	KEY_OVERRIDE           = key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "override name & category"))
+	KEY_NEW_MAPPING        = key.NewBinding(key.WithKeys("m"), key.WithHelp("m", "new mapping from transaction"))
+	KEY_NEW_MAPPING_AMOUNT = key.NewBinding(key.WithKeys("M"), key.WithHelp("M", "new mapping, matching the amount too"))
+
+	KEY_FILTER_APPLY  = key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "apply filter"))
+	KEY_FILTER_CANCEL = key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "cancel filter"))
+
+	KEY_PANE_CLOSE = key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "back to details"))
+)
+
+func init() {
+	keymap.Register("transactions", map[string]*key.Binding{
+		"up":                 &KEY_UP,
+		"down":               &KEY_DOWN,
+		"start":              &KEY_START,
+		"end":                &KEY_END,
+		"scroll_up":          &KEY_SCROLL_UP,
+		"scroll_down":        &KEY_SCROLL_DOWN,
+		"filter":             &KEY_FILTER,
+		"sort_next":          &KEY_SORT_NEXT,
+		"sort_prev":          &KEY_SORT_PREV,
+		"sort_dir":           &KEY_SORT_DIR,
+		"detail":             &KEY_DETAIL,
+		"close":              &KEY_CLOSE,
+		"override":           &KEY_OVERRIDE,
+		"new_mapping":        &KEY_NEW_MAPPING,
+		"new_mapping_amount": &KEY_NEW_MAPPING_AMOUNT,
+	})
+	keymap.Register("transactions.filter", map[string]*key.Binding{
+		"apply":  &KEY_FILTER_APPLY,
+		"cancel": &KEY_FILTER_CANCEL,
+	})
+	keymap.Register("transactions.pane", map[string]*key.Binding{
+		"close": &KEY_PANE_CLOSE,
+	})
+	// The override & mapping editors are in the pane
+	keymap.Related("transactions.pane", "editor")
+}
+
+func (m Model) KeyHelp() []key.Binding {
+	switch {
+	case m.filter.Focused():
+		return keymap.Group("transactions.filter")
+	case m.pane == PANE_OVERRIDE || m.pane == PANE_MAPPING:
+		return append(keymap.Group("transactions.pane"), m.editor.KeyHelp()...)
+	}
+
+	return keymap.Group("transactions")
+}
+
+func (m Model) Typing() bool {
+	if m.pane == PANE_OVERRIDE || m.pane == PANE_MAPPING {
+		return m.editor.Typing()
+	}
+
+	return m.filter.Focused()
+}
+
+// Reports if the filter bar or a pane is open, which esc closes before anything else
+func (m Model) Busy() bool {
+	return m.filter.Focused() || m.pane != PANE_NONE
+}
@@ -1,20 +1,27 @@
 package transactions
 
 import (
+	"context"
 	"log"
 	"slices"
 	"time"
 
+	"charm.land/bubbles/v2/key"
 	"charm.land/bubbles/v2/spinner"
+	"charm.land/bubbles/v2/textinput"
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
 	"github.com/bank_data_tui/api"
+	"github.com/bank_data_tui/config"
 	"github.com/bank_data_tui/styles"
 	"github.com/bank_data_tui/utils"
+	"github.com/bank_data_tui/utils/editor"
+	"github.com/bank_data_tui/utils/notify"
 	"github.com/bank_data_tui/utils/repo"
 )
 
 type Model struct {
+	ctx             context.Context
 	w, h            int
 	selected        int
 	viewportOff     int
@@ -23,34 +30,106 @@ type Model struct {
 	lastDataPage    int
 	api             *api.APIClient
 	cache           *repo.Cache
+	cfg             *config.Config
 	loader          spinner.Model
 	nextPageLoading bool
+	// Set when the last page request failed, stops auto loading until a retry
+	pageFailed bool
+
+	filter    textinput.Model
+	filterErr error
+	// Text of the filter bar that is currently applied
+	appliedFilter string
+	// Currently applied filters, paging & ordering is set on request
+	query api.TransactionQuery
+	// Bumped whenever the query changes, so that responses for old queries can be ignored
+	queryGen int
+
+	sortBy  api.TransactionFields
+	sortAsc bool
+
+	pane     int
+	override *overrideDraft
+	// What the mapping editor saves, while it's open
+	newMapping *api.Mapping
+	editor     *editor.Model
 }
 
-func New(api *api.APIClient, cache *repo.Cache, w, h int) *Model {
-	return &Model{
-		w:     w,
-		h:     h,
-		api:   api,
-		cache: cache,
+const (
+	PANE_NONE = iota
+	PANE_DETAIL
+	PANE_OVERRIDE
+	PANE_MAPPING
+)
+
+// Order in which the sort key cycles through the fields
+var SORT_FIELDS = []api.TransactionFields{api.TOR_AUTH, api.TOR_SETTLE, api.TOR_AMOUNT, api.TOR_CATEGORY}
+
+func New(ctx context.Context, api *api.APIClient, cache *repo.Cache, cfg *config.Config, w, h int) *Model {
+	filter := textinput.New()
+	filter.Prompt = ""
+	filter.Placeholder = FILTER_HELP
+	filter.SetVirtualCursor(false)
+	filter.SetStyles(textinput.Styles{
+		Focused: textinput.StyleState{
+			Text:        styles.S_TEXT_HIGHLIGHT,
+			Placeholder: styles.S_TEXT_DISABLED,
+		},
+		Blurred: textinput.StyleState{
+			Text:        styles.S_TEXT_NORMAL,
+			Placeholder: styles.S_TEXT_DISABLED,
+		},
+		Cursor: styles.TI_CURSOR,
+	})
+
+	m := &Model{
+		ctx:     ctx,
+		w:       w,
+		h:       h,
+		api:     api,
+		cache:   cache,
+		cfg:     cfg,
+		filter:  filter,
+		sortBy:  cfg.SortField(),
+		sortAsc: cfg.Transactions.Asc,
 	}
+	m.filter.SetWidth(m.filterWidth())
+
+	return m
 }
 
 type newPageData struct {
 	*api.RespPages[[]*api.Transaction]
 	page     int
+	gen      int
 	override bool
 }
 
+type pageErr struct {
+	err  error
+	page int
+	gen  int
+}
+
+type retryPage struct {
+	page int
+	gen  int
+}
+
 func (m Model) Init() tea.Cmd {
-	return tea.Batch(m.forceRequestPage(1), func() tea.Msg {
-		_, err := m.cache.EasyCategories(m.api)
-		if err != nil {
-			panic(err)
-		}
+	return tea.Batch(m.forceRequestPage(1), m.fetchCategories())
+}
 
-		return nil
-	})
+// Other screens might have changed how transactions resolve
+func (m Model) Focus() tea.Cmd {
+	return tea.Batch(m.refreshLoaded(), m.fetchCategories())
+}
+
+func (m Model) fetchCategories() tea.Cmd {
+	return func() tea.Msg {
+		_, err := m.cache.EasyCategories(m.ctx, m.api)
+		return notify.Error(err, m.fetchCategories())
+	}
 }
 
 const DE_DUPE_BUFFER = 25
@@ -58,41 +137,76 @@ const DE_DUPE_BUFFER = 25
 func (m Model) Update(msg tea.Msg) (utils.Screen, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyPressMsg:
-		switch msg.String() {
-		case "down":
+		if m.filter.Focused() {
+			return m.updateFilter(msg)
+		}
+		if m.pane == PANE_OVERRIDE || m.pane == PANE_MAPPING {
+			return m.updatePaneEditor(msg)
+		}
+
+		switch {
+		case key.Matches(msg, KEY_FILTER):
+			m.filterErr = nil
+			return m, m.filter.Focus()
+		case key.Matches(msg, KEY_SORT_NEXT):
+			i := slices.Index(SORT_FIELDS, m.sortBy)
+			m.sortBy = SORT_FIELDS[(i+1)%len(SORT_FIELDS)]
+			return m, m.resetPages()
+		case key.Matches(msg, KEY_SORT_PREV):
+			i := slices.Index(SORT_FIELDS, m.sortBy)
+			m.sortBy = SORT_FIELDS[(i-1+len(SORT_FIELDS))%len(SORT_FIELDS)]
+			return m, m.resetPages()
+		case key.Matches(msg, KEY_SORT_DIR):
+			m.sortAsc = !m.sortAsc
+			return m, m.resetPages()
+		case key.Matches(msg, KEY_DETAIL):
+			return m, m.setDetailOpen(m.pane == PANE_NONE)
+		case key.Matches(msg, KEY_CLOSE):
+			return m, m.setDetailOpen(false)
+		case key.Matches(msg, KEY_OVERRIDE):
+			return m, m.openOverride()
+		case key.Matches(msg, KEY_NEW_MAPPING):
+			return m, m.openNewMapping(false)
+		case key.Matches(msg, KEY_NEW_MAPPING_AMOUNT):
+			return m, m.openNewMapping(true)
+		case key.Matches(msg, KEY_DOWN):
 			if m.selected != len(m.items)-1 {
 				m.selected++
 			}
-		case "up":
+		case key.Matches(msg, KEY_UP):
 			if m.selected != 0 {
 				m.selected--
 			}
-		case "end":
+		case key.Matches(msg, KEY_END):
 			m.selected = len(m.items) - 1
-		case "start":
+		case key.Matches(msg, KEY_START):
 			m.selected = 0
-		case "alt+down":
+		case key.Matches(msg, KEY_SCROLL_DOWN):
 			m.viewportOff = m.viewportOff + 1
 			visibleItems := len(m.items) - m.viewportOff
-			if m.h-visibleItems > 8 {
+			if m.listH()-visibleItems > 8 {
 				m.viewportOff--
 			}
 			// if len(m.items) - m.viewportOff < 7 {
 			// 	m.viewportOff--
 			// }
-		case "alt+up":
+		case key.Matches(msg, KEY_SCROLL_UP):
 			m.viewportOff = m.viewportOff - 1
 			if m.viewportOff < 0 {
 				m.viewportOff = 0
 			}
 		}
 
-		if msg.Mod.Contains(tea.ModAlt) {
+		if key.Matches(msg, KEY_SCROLL_UP, KEY_SCROLL_DOWN) {
 			m.forceSelIntoViewport()
 		} else {
 			m.forceViewportIntoSel()
 		}
 	case newPageData:
+		if msg.gen != m.queryGen {
+			break
+		}
+
 		if msg.override {
 			m.items = msg.Data
 		} else {
@@ -111,54 +225,109 @@ func (m Model) Update(msg tea.Msg) (utils.Screen, tea.Cmd) {
 			m.items = append(m.items, sl...)
 		}
 
-		if len(msg.Data) != 50 {
+		if len(msg.Data) != m.cfg.Transactions.PageSize {
 			m.hasHitLastPage = true
 		}
 
 		m.nextPageLoading = false
+		m.pageFailed = false
 		m.lastDataPage = msg.page
+	case refreshedData:
+		if msg.gen != m.queryGen || m.nextPageLoading {
+			break
+		}
+
+		m.items = msg.items
+		m.lastDataPage = msg.lastPage
+		m.hasHitLastPage = msg.hitLast
+		m.selected = min(m.selected, max(len(m.items)-1, 0))
+		m.forceViewportIntoSel()
+	case pageErr:
+		if msg.gen != m.queryGen {
+			break
+		}
+
+		m.nextPageLoading = false
+		m.pageFailed = true
+
+		return m, notify.ErrorCmd(msg.err, func() tea.Msg { return retryPage{page: msg.page, gen: msg.gen} })
+	case retryPage:
+		if msg.gen != m.queryGen {
+			break
+		}
+
+		m.pageFailed = false
+
+		return m, m.reqPage(msg.page)
+	case filterCategoriesLoaded:
+		// Unless it's been changed or given up on in the meantime
+		if m.filter.Focused() && m.filter.Value() == string(msg) {
+			return m.applyFilter()
+		}
+	case mappingsLoaded:
+		m.mappingsLoaded()
+	case editor.ItemNew:
+		return m.mappingSaved()
+	case editor.ItemUpdate, editor.ItemDel:
+		return m.overrideSaved()
 	case utils.ResizeMessage:
 		m.w, m.h = msg.W, msg.H
+		m.filter.SetWidth(m.filterWidth())
+		if m.pane == PANE_OVERRIDE || m.pane == PANE_MAPPING {
+			m.editor.SetWidth(m.paneW())
+		}
 		m.forceViewportIntoSel()
 	}
 
 	batch := []tea.Cmd{}
 	var cmd tea.Cmd
+	if m.pane == PANE_OVERRIDE || m.pane == PANE_MAPPING {
+		// Mostly for the cursor blinks
+		m.editor, cmd = m.editor.Update(msg)
+		batch = append(batch, cmd)
+	}
 	if m.nextPageLoading {
 		m.loader, cmd = m.loader.Update(msg)
 		batch = append(batch, cmd)
 	}
 
-	if !m.hasHitLastPage && !m.nextPageLoading && m.indexIsVisible(-LOAD_OFFSET) {
+	if !m.hasHitLastPage && !m.nextPageLoading && !m.pageFailed && m.indexIsVisible(-LOAD_OFFSET) {
 		batch = append(batch, m.reqPage(m.lastDataPage+1))
 	}
 
 	return m, tea.Batch(batch...)
 }
 
+// Height available for the rows (+ the status row)
+func (m Model) listH() int {
+	return m.h - FILTER_HEIGHT - COL_HEADER_HEIGHT
+}
+
 func (m *Model) forceViewportIntoSel() {
-	if len(m.items) <= m.h {
+	h := m.listH()
+	if len(m.items) <= h {
 		m.viewportOff = 0
 		return
 	}
 
 	if m.selected < m.viewportOff {
 		m.viewportOff = m.selected
-	} else if m.selected > m.viewportOff+m.h-2 {
-		m.viewportOff = m.selected - m.h + 2
+	} else if m.selected > m.viewportOff+h-2 {
+		m.viewportOff = m.selected - h + 2
 	}
 }
 
 func (m *Model) forceSelIntoViewport() {
-	if len(m.items) <= m.h {
+	h := m.listH()
+	if len(m.items) <= h {
 		m.viewportOff = 0
 		return
 	}
 
 	if m.selected < m.viewportOff {
 		m.selected = m.viewportOff
-	} else if m.selected > m.viewportOff+m.h-2 {
-		m.selected = m.viewportOff + m.h - 2
+	} else if m.selected > m.viewportOff+h-2 {
+		m.selected = m.viewportOff + h - 2
 	}
 }
 
@@ -170,7 +339,7 @@ func (m Model) indexIsVisible(n int) bool {
 		n += len(m.items)
 	}
 
-	return m.viewportOff <= n && n <= m.viewportOff+m.h
+	return m.viewportOff <= n && n <= m.viewportOff+m.listH()
 }
 
 const REQ_DEDUPE_PERIOD = 1 * time.Minute
@@ -191,18 +360,93 @@ func (m *Model) forceRequestPage(n int) tea.Cmd {
 		spinner.WithStyle(lipgloss.NewStyle().Foreground(styles.COLOR_MAIN)),
 	)
 
+	q := m.pageQuery(n)
+	gen := m.queryGen
+
 	return tea.Batch(
 		func() tea.Msg {
-			d, err := m.api.TransactionsFetch(api.TOR_AUTH, n, false)
+			d, err := m.api.TransactionsFetch(m.ctx, q)
 			if err != nil {
-				log.Panicln(err)
+				return pageErr{err: err, page: n, gen: gen}
 			}
 
 			return newPageData{
 				RespPages: d,
 				page:      n,
+				gen:       gen,
 			}
 		},
 		m.loader.Tick,
 	)
 }
+
+func (m Model) pageQuery(n int) api.TransactionQuery {
+	q := m.query
+	q.Page = n
+	q.PageSize = m.cfg.Transactions.PageSize
+	q.OrderBy = m.sortBy
+	q.Asc = m.sortAsc
+
+	return q
+}
+
+type refreshedData struct {
+	items    []*api.Transaction
+	gen      int
+	lastPage int
+	hitLast  bool
+}
+
+// Re-requests every loaded page, while keeping the selection & scroll position
+func (m *Model) refreshLoaded() tea.Cmd {
+	pages := max(m.lastDataPage, 1)
+	gen := m.queryGen
+	queries := make([]api.TransactionQuery, pages)
+	for i := range queries {
+		queries[i] = m.pageQuery(i + 1)
+	}
+
+	var cmd tea.Cmd
+	cmd = func() tea.Msg {
+		res := refreshedData{gen: gen}
+		seen := map[string]bool{}
+
+		for _, q := range queries {
+			d, err := m.api.TransactionsFetch(m.ctx, q)
+			if err != nil {
+				return notify.Error(err, cmd)
+			}
+
+			for _, t := range d.Data {
+				if !seen[t.ID] {
+					seen[t.ID] = true
+					res.items = append(res.items, t)
+				}
+			}
+
+			res.lastPage = q.Page
+			if len(d.Data) != m.cfg.Transactions.PageSize {
+				res.hitLast = true
+				break
+			}
+		}
+
+		return res
+	}
+
+	return cmd
+}
+
+// Throws away every loaded page & starts again from page 1 with the current query
+func (m *Model) resetPages() tea.Cmd {
+	m.queryGen++
+	m.items = nil
+	m.selected = 0
+	m.viewportOff = 0
+	m.lastDataPage = 0
+	m.hasHitLastPage = false
+	m.pageFailed = false
+	m.nextPageLoading = false
+
+	return m.forceRequestPage(1)
+}
@@ -0,0 +1,67 @@
+package transactions
+
+import (
+	tea "charm.land/bubbletea/v2"
+	"github.com/bank_data_tui/api"
+	"github.com/bank_data_tui/screens/mappings"
+	"github.com/bank_data_tui/utils"
+	"github.com/bank_data_tui/utils/notify"
+)
+
+// Opens the mapping editor, prefilled to match the selected transaction
+func (m *Model) openNewMapping(withAmount bool) tea.Cmd {
+	t := m.selectedTransaction()
+	if t == nil {
+		return nil
+	}
+
+	cmd := m.setDetailOpen(true)
+	m.pane = PANE_MAPPING
+
+	v := &api.Mapping{
+		Name:    t.Desc,
+		InpText: mappings.DescRegex(t.Desc),
+	}
+	if withAmount {
+		amt := t.Amount
+		v.InpAmt = &amt
+	}
+	if t.ResolvedName != nil {
+		v.ResName = *t.ResolvedName
+	}
+	if m.cache.Mappings != nil {
+		v.Priority = mappings.NextPriority(m.cache.Mappings)
+	}
+
+	m.newMapping = v
+	m.editor = mappings.NewEditor(m.ctx, m.api, m.cache, m.paneW(), v)
+
+	return tea.Batch(cmd, m.editor.Init())
+}
+
+// The mappings weren't loaded when the editor was opened, so its priority is filled in now unless it's been typed
+func (m *Model) mappingsLoaded() {
+	if m.pane != PANE_MAPPING || m.newMapping.Priority != 0 {
+		return
+	}
+
+	m.newMapping.Priority = mappings.NextPriority(m.cache.Mappings)
+	m.editor.Refresh()
+}
+
+func (m Model) mappingSaved() (utils.Screen, tea.Cmd) {
+	if m.pane != PANE_MAPPING {
+		return m, nil
+	}
+
+	m.pane = PANE_DETAIL
+	m.cache.Mappings = nil
+	// Which the new mapping resolved differently
+	m.cache.Sample = nil
+
+	return m, tea.Batch(
+		notify.Info("Mapping created"),
+		m.refreshLoaded(),
+		m.fetchMappings(),
+	)
+}
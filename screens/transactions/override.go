@@ -0,0 +1,171 @@
+package transactions
+
+import (
+	"fmt"
+	"strings"
+
+	"charm.land/bubbles/v2/key"
+	"charm.land/bubbles/v2/textinput"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/bank_data_tui/api"
+	"github.com/bank_data_tui/styles"
+	"github.com/bank_data_tui/utils"
+	"github.com/bank_data_tui/utils/editor"
+)
+
+type overrideDraft struct {
+	name       string
+	categoryID string
+}
+
+// Saving replaces the whole override, so both are sent as they are. Clearing both removes it
+func (d *overrideDraft) toAPI() *api.TransactionOverride {
+	o := &api.TransactionOverride{}
+	if d.name != "" {
+		o.Name = &d.name
+	}
+	if d.categoryID != "" {
+		o.CategoryID = &d.categoryID
+	}
+
+	return o
+}
+
+func (m *Model) openOverride() tea.Cmd {
+	t := m.selectedTransaction()
+	if t == nil {
+		return nil
+	}
+
+	cmd := m.setDetailOpen(true)
+	m.pane = PANE_OVERRIDE
+
+	d := &overrideDraft{}
+	switch {
+	case t.Override != nil:
+		if t.Override.Name != nil {
+			d.name = *t.Override.Name
+		}
+		if t.Override.CategoryID != nil {
+			d.categoryID = *t.Override.CategoryID
+		}
+	case t.Overridden:
+		// The server didn't say which, so it's whatever it resolved to. Saving keeps both
+		if t.ResolvedName != nil {
+			d.name = *t.ResolvedName
+		}
+		if t.ResolvedCategoryID != nil {
+			d.categoryID = *t.ResolvedCategoryID
+		}
+	}
+	m.override = d
+
+	m.editor = m.newOverrideEditor(t.ID, m.override)
+
+	return tea.Batch(cmd, m.editor.Init())
+}
+
+func (m *Model) newOverrideEditor(id string, d *overrideDraft) *editor.Model {
+	return editor.New(
+		m.paneW(),
+		id,
+		[]*editor.DataField{
+			{
+				Title: "Name",
+				ID:    "name",
+				Value: &d.name,
+				Row:   0,
+			},
+			{
+				Title: "Category",
+				ID:    "categoryId",
+				GetValue: func() string {
+					if cat := m.cache.Category(d.categoryID); cat != nil {
+						return cat.Name
+					}
+
+					return ""
+				},
+				SetValue: func(raw string) {
+					d.categoryID = ""
+					for _, c := range m.cache.Categories {
+						if strings.EqualFold(raw, c.Name) {
+							d.categoryID = c.ID
+							return
+						}
+					}
+				},
+				Row: 1,
+			},
+		},
+		func(alt bool) (string, error) {
+			// Transactions always exist already
+			return id, nil
+		},
+		func(alt bool, id string) error {
+			return m.api.TransactionUpdate(m.ctx, id, d.toAPI())
+		},
+		func(alt bool, id string) error {
+			return m.api.TransactionUpdate(m.ctx, id, &api.TransactionOverride{})
+		},
+		editor.AddFieldValidator(1, func(s string) error {
+			if s == "" {
+				return nil
+			}
+
+			for _, c := range m.cache.Categories {
+				if strings.EqualFold(s, c.Name) {
+					return nil
+				}
+			}
+
+			return fmt.Errorf("Must be a valid category")
+		}),
+		func(fields []*textinput.Model) {
+			sl := make([]string, len(m.cache.Categories))
+			for i, v := range m.cache.Categories {
+				sl[i] = v.Name
+			}
+
+			fields[1].ShowSuggestions = true
+			fields[1].SetSuggestions(sl)
+		},
+	)
+}
+
+func (m Model) updatePaneEditor(msg tea.KeyPressMsg) (utils.Screen, tea.Cmd) {
+	if key.Matches(msg, KEY_PANE_CLOSE) {
+		m.pane = PANE_DETAIL
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.editor, cmd = m.editor.Update(msg)
+
+	return m, cmd
+}
+
+// What the row resolves to now is up to the server (the mappings might not be loaded), so it's refetched
+func (m Model) overrideSaved() (utils.Screen, tea.Cmd) {
+	if m.pane != PANE_OVERRIDE {
+		return m, nil
+	}
+
+	m.pane = PANE_DETAIL
+	m.cache.Sample = nil
+
+	return m, m.refreshLoaded()
+}
+
+func (m Model) renderEditor(title, hint string, w int) (string, *tea.Cursor) {
+	title = lipgloss.NewStyle().Bold(true).Foreground(styles.COLOR_MAIN).Render(title)
+	hint = styles.S_TEXT_DISABLED.Render(hint)
+
+	e, cur := m.editor.View()
+	if cur != nil {
+		cur.Y += 3
+	}
+
+	return utils.Overflow(title+"\n"+hint+"\n\n"+e, w), cur
+}
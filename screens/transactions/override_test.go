@@ -0,0 +1,44 @@
+package transactions
+
+import "testing"
+
+func TestOverrideToAPI(t *testing.T) {
+	for _, c := range []struct {
+		name         string
+		d            overrideDraft
+		wantName     string
+		wantCategory string
+	}{
+		// Prefilled from the resolved values, as the server didn't say which was overridden. Saving it unchanged
+		// mustn't clear the override
+		{"unchanged", overrideDraft{name: "Shop", categoryID: "1"}, "Shop", "1"},
+		{"only the name", overrideDraft{name: "Shop"}, "Shop", ""},
+		{"only the category", overrideDraft{categoryID: "2"}, "", "2"},
+		{"both cleared", overrideDraft{}, "", ""},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			o := c.d.toAPI()
+
+			name, cat := "", ""
+			if o.Name != nil {
+				name = *o.Name
+			}
+			if o.CategoryID != nil {
+				cat = *o.CategoryID
+			}
+			if name != c.wantName || cat != c.wantCategory {
+				t.Errorf("sent name %q & category %q, want %q & %q", name, cat, c.wantName, c.wantCategory)
+			}
+		})
+	}
+
+	// Changing the category keeps a name that's already overridden
+	d := &overrideDraft{name: "Shop", categoryID: "1"}
+	d.categoryID = "2"
+	if o := d.toAPI(); o.Name == nil || *o.Name != "Shop" {
+		t.Errorf("changing the category dropped the name: %+v", o)
+	}
+	if o := (&overrideDraft{}).toAPI(); !o.Empty() {
+		t.Errorf("clearing both should clear the override: %+v", o)
+	}
+}
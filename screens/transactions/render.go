@@ -4,6 +4,7 @@ import (
 	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
@@ -19,22 +20,119 @@ import (
 
 const COL_SPLIT = "│"
 
+const COL_HEADER_HEIGHT = 1
+
+// Prefix of the name of transactions that were categorised by hand
+const OVERRIDE_MARKER = "✎ "
+
+const (
+	SORT_ASC  = "▲"
+	SORT_DESC = "▼"
+)
+
+const (
+	FILTER_PROMPT = "/ "
+	// 1 line for the bar, 1 for the error (or just spacing)
+	FILTER_HEIGHT = 2
+)
+
+func (m Model) filterWidth() int {
+	return max(m.listW()-lipgloss.Width(FILTER_PROMPT)-1, 1)
+}
+
+func (m Model) renderFilter() (string, *tea.Cursor) {
+	promptStyle := styles.S_TEXT_DISABLED
+	if m.filter.Focused() {
+		promptStyle = styles.S_TEXT_HIGHLIGHT
+	} else if m.query.Filtered() {
+		promptStyle = styles.S_TEXT_HIGHLIGHT_SECONDARY
+	}
+
+	// The placeholder doesn't care about the width of the input
+	bar := utils.Overflow(promptStyle.Render(FILTER_PROMPT)+m.filter.View(), m.listW())
+
+	var errLine string
+	if m.filterErr != nil {
+		errLine = lipgloss.NewStyle().Foreground(styles.COLOR_WRONG).Faint(true).Italic(true).Render(
+			utils.Overflow(m.filterErr.Error(), m.listW()),
+		)
+	}
+
+	var cur *tea.Cursor
+	if m.filter.Focused() {
+		cur = m.filter.Cursor()
+		if cur != nil {
+			cur.X += lipgloss.Width(FILTER_PROMPT)
+		}
+	}
+
+	return bar + "\n" + errLine, cur
+}
+
 func (m *Model) cols() []int {
 	colCunt := 4
 
 	icon := 2
-	amt := 8
-	date := 10
+	amt := max(8, lipgloss.Width(m.cfg.Amount.Format(-9999.99)))
+	// September & Wednesday are the widest names, for formats that spell them out
+	date := lipgloss.Width(m.cfg.FormatDate(time.Date(2006, time.September, 27, 23, 59, 59, 0, time.Local)))
 
 	// space padding on either side + content
 	colTotalWidth := lipgloss.Width(COL_SPLIT)*colCunt + colCunt*2
-	leftover := m.w - icon - amt - date - colTotalWidth
+	leftover := m.listW() - icon - amt - date - colTotalWidth
 
 	nameLen := int(float64(leftover) * 0.6)
 
 	return []int{icon, nameLen, leftover - nameLen, date, amt}
 }
 
+// Which column of cols() the sort field is shown in
+func sortCol(f api.TransactionFields) int {
+	switch f {
+	case api.TOR_CATEGORY:
+		return 0
+	case api.TOR_AMOUNT:
+		return 4
+	}
+
+	return 3
+}
+
+func (m Model) renderColHeader() string {
+	str := []string{"", "Name", "Description", "Authed", "Amount"}
+	if m.sortBy == api.TOR_SETTLE {
+		str[3] = "Settled"
+	}
+
+	ind := SORT_DESC
+	if m.sortAsc {
+		ind = SORT_ASC
+	}
+
+	sc := sortCol(m.sortBy)
+	if sc == 0 {
+		str[sc] = ind
+	} else {
+		str[sc] += " " + ind
+	}
+
+	style := lipgloss.NewStyle().Bold(true).Foreground(styles.COLOR_MAIN)
+	for i, w := range m.cols() {
+		str[i] = style.Width(w).Render(utils.Overflow(str[i], w))
+	}
+
+	return m.joinCols(str, style)
+}
+
+func (m Model) joinCols(str []string, rowStyle lipgloss.Style) string {
+	colSplitter := rowStyle.Render(" " + COL_SPLIT + " ")
+
+	// str[0] alr has a space in it
+	return str[0] + COL_SPLIT + rowStyle.Render(
+		" "+strings.Join(str[1:], colSplitter),
+	)
+}
+
 func (m Model) renderRow(t *api.Transaction, selected bool) string {
 	rowStyle := lipgloss.NewStyle()
 	if selected {
@@ -55,14 +153,21 @@ func (m Model) renderRow(t *api.Transaction, selected bool) string {
 	}
 
 	str[2] = t.Desc
-	str[3] = t.AuthedAt.Format("02/01/2006")
+	if m.sortBy == api.TOR_SETTLE {
+		str[3] = m.cfg.FormatDate(t.SettledAt)
+	} else {
+		str[3] = m.cfg.FormatDate(t.AuthedAt)
+	}
 
 	if t.ResolvedName != nil {
 		str[1] = *t.ResolvedName
 		str[2] = lipgloss.NewStyle().Faint(true).Render(str[2])
 	}
+	if t.Overridden {
+		str[1] = OVERRIDE_MARKER + str[1]
+	}
 
-	str[4] = strconv.FormatFloat(t.Amount, 'f', 2, 64)
+	str[4] = m.cfg.Amount.Format(t.Amount)
 
 	for i, w := range cols {
 		base := rowStyle
@@ -91,25 +196,34 @@ func (m Model) renderRow(t *api.Transaction, selected bool) string {
 		).Foreground(fg).Width(2).Render(str[0])
 	}
 
-	colSplitter := rowStyle.Render(" " + COL_SPLIT + " ")
-
-	// str[0] alr has a space in it
-	return str[0] + COL_SPLIT + rowStyle.Render(
-		" "+strings.Join(str[1:], colSplitter),
-	)
+	return m.joinCols(str, rowStyle)
 }
 
-func (m Model) View() (string, *tea.Cursor) {
-	if m.h == 0 || len(m.items) == 0 {
+func (m Model) viewList() (string, *tea.Cursor) {
+	if m.h == 0 {
 		return "", nil
 	}
 
+	filter, cur := m.renderFilter()
+	h := m.listH()
+
+	if len(m.items) == 0 {
+		msg := "No Transactions!"
+		if m.nextPageLoading {
+			msg = m.loader.View() + lipgloss.NewStyle().Faint(true).Render(" Loading")
+		} else if m.pageFailed {
+			msg = styles.S_TEXT_WRONG.Render("Couldn't load transactions")
+		}
+
+		return filter + "\n" + m.renderColHeader() + "\n" + lipgloss.Place(m.listW(), h, lipgloss.Center, lipgloss.Center, msg), cur
+	}
+
 	items := m.items[m.viewportOff:]
 	// 1 line empty at the bottom
-	items = items[:min(len(items), m.h-1)]
+	items = items[:min(len(items), h-1)]
 
 	if len(items) == 0 {
-		return "No Items here!", nil
+		return filter + "\n" + m.renderColHeader() + "\n" + "No Items here!", cur
 	}
 
 	rows := ""
@@ -117,7 +231,11 @@ func (m Model) View() (string, *tea.Cursor) {
 		rows += m.renderRow(v, m.selected == m.viewportOff+i) + "\n"
 	}
 
-	lastRowItems := []string{"Total Transactions: " + strconv.Itoa(len(m.items))}
+	total := "Total Transactions: " + strconv.Itoa(len(m.items))
+	if m.query.Filtered() {
+		total += styles.S_TEXT_HIGHLIGHT_SECONDARY.Render(" (filtered)")
+	}
+	lastRowItems := []string{total}
 	if m.nextPageLoading {
 		loading := m.loader.View()
 		lastRowItems = append(
@@ -128,11 +246,11 @@ func (m Model) View() (string, *tea.Cursor) {
 
 	rows = rows[:len(rows)-1]
 
-	if m.hasHitLastPage && m.h-len(items) > 3 {
-		rows += "\n\n\n" + lipgloss.PlaceHorizontal(m.w, lipgloss.Center, "No More Transactions!")
+	if m.hasHitLastPage && h-len(items) > 3 {
+		rows += "\n\n\n" + lipgloss.PlaceHorizontal(m.listW(), lipgloss.Center, "No More Transactions!")
 	}
 
-	res, _ := utils.JoinHorizontalWithSpacer(m.w, 1, lastRowItems...)
+	res, _ := utils.JoinHorizontalWithSpacer(m.listW(), 1, lastRowItems...)
 
-	return rows + strings.Repeat("\n", m.h-strings.Count(rows, "\n")-1) + res, nil
+	return filter + "\n" + m.renderColHeader() + "\n" + rows + strings.Repeat("\n", h-strings.Count(rows, "\n")-1) + res, cur
 }
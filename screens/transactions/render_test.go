@@ -0,0 +1,65 @@
+package transactions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bank_data_tui/api"
+	"github.com/bank_data_tui/config"
+	"github.com/bank_data_tui/utils"
+	"github.com/bank_data_tui/utils/repo"
+	"github.com/bank_data_tui/utils/screentest"
+)
+
+func newScreen(c *api.APIClient, w, h int) utils.Screen {
+	return New(context.Background(), c, &repo.Cache{}, config.Default(), w, h)
+}
+
+func TestView(t *testing.T) {
+	screentest.Sizes(t, screentest.SIZES, func(t *testing.T, w, h int) {
+		sh := screentest.ScreenHarness(t, w, h, newScreen)
+		sh.Golden(t.Name())
+
+		sh.Keys("down", "down", "enter").Settle()
+		sh.Golden(t.Name() + "_detail")
+
+		sh.Keys("esc", "s", "r").Settle()
+		sh.Golden(t.Name() + "_sorted")
+	})
+}
+
+func TestViewFilter(t *testing.T) {
+	screentest.Sizes(t, screentest.SIZES, func(t *testing.T, w, h int) {
+		sh := screentest.ScreenHarness(t, w, h, newScreen)
+
+		sh.Keys("/").Type("max:-20 tesco")
+		sh.Golden(t.Name() + "_typing")
+
+		sh.Keys("enter").Settle()
+		sh.Golden(t.Name())
+
+		sh.Keys("/").Type(" min:abc")
+		sh.Keys("enter").Settle()
+		sh.Golden(t.Name() + "_bad")
+	})
+}
+
+func TestViewResize(t *testing.T) {
+	sh := screentest.ScreenHarness(t, 120, 35, newScreen)
+	sh.Keys("end").Settle()
+
+	sh.Send(utils.ResizeMessage{W: 60, H: 15}).Settle()
+	sh.Golden(t.Name())
+	// The selection & sort arrows are only told apart by their styles
+	sh.GoldenANSI(t.Name())
+}
+
+// Straight after opening, before the mappings are loaded, so the priority comes in after
+func TestViewNewMapping(t *testing.T) {
+	screentest.Sizes(t, screentest.SIZES, func(t *testing.T, w, h int) {
+		sh := screentest.ScreenHarness(t, w, h, newScreen)
+
+		sh.Keys("m").Settle()
+		sh.Golden(t.Name())
+	})
+}
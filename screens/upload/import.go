@@ -0,0 +1,295 @@
+package upload
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/bank_data_tui/styles"
+	"github.com/bank_data_tui/utils"
+	"github.com/bank_data_tui/utils/backup"
+	"github.com/bank_data_tui/utils/notify"
+)
+
+// Exports are named like bank_data_export-2024-03-14.yaml, with a -2, -3... if there's one from that day already
+const (
+	EXPORT_FILE        = "bank_data_export"
+	EXPORT_FILE_EXT    = ".yaml"
+	EXPORT_DATE_FORMAT = "2006-01-02"
+)
+
+type importState struct {
+	path     string
+	plan     *backup.Plan
+	applying bool
+	off      int
+}
+
+type planReady struct {
+	path string
+	plan *backup.Plan
+}
+
+type importDone struct {
+	done int
+}
+
+type exported struct {
+	path string
+}
+
+var (
+	STYLE_ACT  map[backup.Action]lipgloss.Style
+	ACT_PREFIX = map[backup.Action]string{
+		backup.ACT_CREATE: "+ ",
+		backup.ACT_UPDATE: "~ ",
+		backup.ACT_DELETE: "- ",
+	}
+)
+
+func init() {
+	styles.OnTheme(func() {
+		STYLE_ACT = map[backup.Action]lipgloss.Style{
+			backup.ACT_CREATE: lipgloss.NewStyle().Foreground(lipgloss.ANSIColor(2)),
+			backup.ACT_UPDATE: styles.S_TEXT_HIGHLIGHT_SECONDARY,
+			backup.ACT_DELETE: styles.S_TEXT_WRONG,
+		}
+	})
+}
+
+func (m Model) diffCmd(p string) tea.Cmd {
+	return func() tea.Msg {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return notify.Error(err, nil)
+		}
+
+		doc, err := backup.Decode(data, backup.FormatFromPath(p))
+		if err != nil {
+			return notify.Error(err, nil)
+		}
+
+		plan, err := backup.Diff(m.ctx, m.api, doc)
+		if err != nil {
+			return notify.Error(err, m.diffCmd(p))
+		}
+
+		return planReady{path: p, plan: plan}
+	}
+}
+
+func (m Model) applyCmd(noRetroactive bool) tea.Cmd {
+	plan, p := m.importing.plan, m.importing.path
+
+	return func() tea.Msg {
+		done, err := plan.Apply(m.ctx, m.api, noRetroactive)
+		if err != nil {
+			// Some of it might have gone through, so retrying needs a fresh diff
+			return tea.BatchMsg{
+				func() tea.Msg { return importDone{done: done} },
+				func() tea.Msg { return notify.Error(err, m.diffCmd(p)) },
+			}
+		}
+
+		return importDone{done: done}
+	}
+}
+
+func (m Model) exportCmd() tea.Cmd {
+	dir := m.filepicker.Dir()
+
+	return func() tea.Msg {
+		doc, err := backup.Export(m.ctx, m.api)
+		if err != nil {
+			return notify.Error(err, m.exportCmd())
+		}
+
+		data, err := doc.Encode(backup.FormatFromPath(EXPORT_FILE_EXT))
+		if err != nil {
+			return notify.Error(err, nil)
+		}
+
+		p, err := writeNew(dir, EXPORT_FILE+"-"+time.Now().Format(EXPORT_DATE_FORMAT), EXPORT_FILE_EXT, data)
+		if err != nil {
+			return notify.Error(err, nil)
+		}
+
+		return exported{path: p}
+	}
+}
+
+// Writes data to name+ext in dir, or name-2+ext & so on, without ever replacing a file. Returns the path used
+func writeNew(dir, name, ext string, data []byte) (string, error) {
+	for i := 1; ; i++ {
+		p := filepath.Join(dir, name+ext)
+		if i > 1 {
+			p = filepath.Join(dir, name+"-"+strconv.Itoa(i)+ext)
+		}
+
+		f, err := os.OpenFile(p, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if errors.Is(err, fs.ErrExist) {
+			continue
+		} else if err != nil {
+			return "", err
+		}
+
+		_, err = f.Write(data)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+
+		return p, err
+	}
+}
+
+func (m Model) updateImport(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case importDone:
+		m.importing = nil
+		// Other screens have these cached
+		m.cache.Categories = nil
+		m.cache.Mappings = nil
+		// Resolved again against the new mappings
+		m.cache.Sample = nil
+
+		return m, notify.Info("Import applied " + strconv.Itoa(msg.done) + " changes")
+	case tea.KeyPressMsg:
+		if m.importing.applying {
+			return m, nil
+		}
+
+		switch {
+		case key.Matches(msg, KEY_IMPORT_CANCEL):
+			m.importing = nil
+		case key.Matches(msg, KEY_IMPORT_APPLY):
+			if m.importing.plan.Empty() {
+				m.importing = nil
+				break
+			}
+			m.importing.applying = true
+			return m, tea.Batch(m.applyCmd(false), m.spin.Tick)
+		case key.Matches(msg, KEY_IMPORT_APPLY_NEW):
+			if m.importing.plan.Empty() {
+				break
+			}
+			m.importing.applying = true
+			return m, tea.Batch(m.applyCmd(true), m.spin.Tick)
+		case key.Matches(msg, KEY_IMPORT_PRUNE):
+			m.importing.plan.Prune = !m.importing.plan.Prune
+		case key.Matches(msg, KEY_IMPORT_UP):
+			m.importing.off = max(0, m.importing.off-1)
+		case key.Matches(msg, KEY_IMPORT_DOWN):
+			m.importing.off++
+		}
+		m.scrollImport()
+	default:
+		var cmd tea.Cmd
+		m.spin, cmd = m.spin.Update(msg)
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+// Lines of changes under the summary
+func (m Model) importLines() []string {
+	imp := m.importing
+	lines := []string{}
+	for _, ch := range imp.plan.Changes {
+		kind := "mapping"
+		if ch.Category != nil {
+			kind = "category"
+		}
+
+		if ch.Duplicate && !imp.plan.Prune {
+			lines = append(lines, styles.S_TEXT_DISABLED.Render("  "+kind+" "+ch.Name()+" (duplicate, kept)"))
+			continue
+		}
+		if ch.Repoint && !imp.plan.Prune {
+			// Nothing to move it off then
+			continue
+		}
+
+		lines = append(lines, STYLE_ACT[ch.Action].Render(ACT_PREFIX[ch.Action]+kind+" ")+ch.Name())
+		for _, d := range ch.Diffs {
+			lines = append(lines, styles.S_TEXT_DISABLED.Render("    "+d))
+		}
+	}
+	if imp.plan.Empty() {
+		lines = append(lines, "Server already matches this file")
+	}
+
+	return lines
+}
+
+// Room for the lines, under the path, summary & an empty line, & above an empty line & the keys
+func (m Model) importRowsH() int {
+	return max(1, m.h-5)
+}
+
+// Keeps the scroll within the lines, as the height or what's shown (ie. pruning) changes
+func (m Model) scrollImport() {
+	m.importing.off = min(m.importing.off, max(0, len(m.importLines())-m.importRowsH()))
+}
+
+func (m Model) viewImport() string {
+	imp := m.importing
+	lines := m.importLines()
+
+	c, u, d := imp.plan.Summary()
+	summary := strconv.Itoa(c) + " to create, " + strconv.Itoa(u) + " to update, " + strconv.Itoa(d) + " to delete"
+	dupes := imp.plan.Duplicates()
+	if dupes != 0 && !imp.plan.Prune {
+		summary += styles.S_TEXT_DISABLED.Render(", " + strconv.Itoa(dupes) + " duplicates kept")
+	}
+	head := []string{
+		styles.S_TEXT_HIGHLIGHT.Render("Import ") + imp.path,
+		summary,
+		"",
+	}
+
+	var foot string
+	switch {
+	case imp.applying:
+		foot = m.spin.View() + " " + styles.S_TEXT_HIGHLIGHT_SECONDARY.Render("Applying...")
+	case imp.plan.Empty():
+		foot = styles.S_TEXT_DISABLED.Render(KEY_IMPORT_APPLY.Help().Key + "/" + KEY_IMPORT_CANCEL.Help().Key + " to go back")
+	default:
+		keys := []key.Binding{KEY_IMPORT_APPLY, KEY_IMPORT_APPLY_NEW}
+		if dupes != 0 {
+			prune := KEY_IMPORT_PRUNE
+			if imp.plan.Prune {
+				prune.SetHelp(prune.Help().Key, "keep duplicates")
+			}
+			keys = append(keys, prune)
+		}
+		keys = append(keys, KEY_IMPORT_CANCEL)
+
+		hints := []string{}
+		for _, k := range keys {
+			hints = append(hints, k.Help().Key+" "+k.Help().Desc)
+		}
+		foot = styles.S_TEXT_DISABLED.Render(strings.Join(hints, " · "))
+	}
+
+	rowsH := m.importRowsH()
+	lines = lines[min(imp.off, len(lines)):]
+	if len(lines) > rowsH {
+		lines = lines[:rowsH]
+	}
+
+	all := append(head, lines...)
+	all = append(all, "", foot)
+	for i, l := range all {
+		all[i] = utils.Overflow(l, m.w)
+	}
+
+	return strings.Join(all, "\n")
+}
@@ -0,0 +1,47 @@
+package upload
+
+import (
+	"charm.land/bubbles/v2/key"
+	"github.com/bank_data_tui/utils/keymap"
+)
+
+var (
+	KEY_EXPORT = key.NewBinding(key.WithKeys("alt+e"), key.WithHelp("alt+e", "export mappings & categories"))
+
+	// While looking at an import
+	KEY_IMPORT_APPLY     = key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "apply"))
+	KEY_IMPORT_APPLY_NEW = key.NewBinding(key.WithKeys("alt+enter"), key.WithHelp("alt+enter", "apply without touching old transactions"))
+	KEY_IMPORT_PRUNE     = key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "delete duplicates too"))
+	KEY_IMPORT_CANCEL    = key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "cancel"))
+	KEY_IMPORT_UP        = key.NewBinding(key.WithKeys("up"), key.WithHelp("↑", "scroll up"))
+	KEY_IMPORT_DOWN      = key.NewBinding(key.WithKeys("down"), key.WithHelp("↓", "scroll down"))
+)
+
+func init() {
+	keymap.Register("upload", map[string]*key.Binding{
+		"export": &KEY_EXPORT,
+	})
+	keymap.Register("upload.import", map[string]*key.Binding{
+		"apply":          &KEY_IMPORT_APPLY,
+		"apply_new_only": &KEY_IMPORT_APPLY_NEW,
+		"prune":          &KEY_IMPORT_PRUNE,
+		"cancel":         &KEY_IMPORT_CANCEL,
+		"up":             &KEY_IMPORT_UP,
+		"down":           &KEY_IMPORT_DOWN,
+	})
+	// The picker's modes are never active together, so they're related to upload one at a time
+	keymap.Related("upload", "filepicker", "filepicker.input")
+	keymap.Related("upload", "filepicker", "filepicker.picker")
+}
+
+func (m Model) KeyHelp() []key.Binding {
+	if m.importing != nil {
+		return keymap.Group("upload.import")
+	}
+
+	return append(keymap.Group("upload"), m.filepicker.KeyHelp()...)
+}
+
+func (m Model) Typing() bool {
+	return m.importing == nil && m.uploadingPath == "" && m.filepicker.Typing()
+}
@@ -1,26 +1,36 @@
 package upload
 
 import (
+	"context"
+	"errors"
 	"log"
 	"os"
 	"time"
 
 	"charm.land/bubbles/v2/spinner"
+	"charm.land/bubbles/v2/key"
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
 	"github.com/bank_data_tui/api"
 	"github.com/bank_data_tui/styles"
 	"github.com/bank_data_tui/utils"
+	"github.com/bank_data_tui/utils/backup"
 	"github.com/bank_data_tui/utils/filepicker"
+	"github.com/bank_data_tui/utils/notify"
+	"github.com/bank_data_tui/utils/repo"
 )
 
 type Model struct {
+	ctx           context.Context
 	api           *api.APIClient
+	cache         *repo.Cache
 	filepicker    filepicker.Model
 	uploadingPath string
 	err           error
 	spin          spinner.Model
-	w, h          int
+	// Set while a mappings/categories document is being imported
+	importing *importState
+	w, h      int
 }
 
 const INP_PADDING = 5
@@ -29,14 +39,16 @@ type uploaded struct {
 	err error
 }
 
-func New(api *api.APIClient, w, h int) *Model {
+func New(ctx context.Context, api *api.APIClient, cache *repo.Cache, w, h int) *Model {
 	m := &Model{
-		api: api,
+		ctx:   ctx,
+		api:   api,
+		cache: cache,
 		w:   w, h: h,
 		spin: spinner.New(spinner.WithStyle(styles.S_TEXT_HIGHLIGHT)),
 	}
 
-	fp := filepicker.New(w, h, []string{"tsv", "csv"})
+	fp := filepicker.New(w, h, []string{"tsv", "csv", "yaml", "yml", "json"})
 
 	m.filepicker = fp
 
@@ -50,6 +62,10 @@ func (m Model) Init() tea.Cmd {
 func (m Model) View() (string, *tea.Cursor) {
 	box := lipgloss.NewStyle().Width(m.w).Height(m.h).Align(lipgloss.Left, lipgloss.Top)
 
+	if m.importing != nil {
+		return box.Render(m.viewImport()), nil
+	}
+
 	if m.uploadingPath == "" {
 		res, cur := m.filepicker.View()
 		return box.Render(res), cur
@@ -82,10 +98,26 @@ func (m Model) Update(msg tea.Msg) (utils.Screen, tea.Cmd) {
 	case utils.ResizeMessage:
 		m.w, m.h = msg.W, msg.H
 		m.filepicker.SetSize(msg.W, msg.H)
+		if m.importing != nil {
+			m.scrollImport()
+		}
 		return m, nil
+	case planReady:
+		m.importing = &importState{path: msg.path, plan: msg.plan}
+		return m, nil
+	case exported:
+		return m, notify.Info("Exported mappings & categories to " + msg.path)
 	case uploaded:
+		// Even a failed upload could've added some
+		m.cache.Sample = nil
+
 		var cmd tea.Cmd
-		if msg.err != nil {
+		var expired *api.SessionExpiredErr
+		if errors.As(msg.err, &expired) {
+			// The login screen takes over, there's nobody to show the error to
+			m.uploadingPath = ""
+			cmd = notify.ErrorCmd(msg.err, nil)
+		} else if msg.err != nil {
 			m.err = msg.err
 			cmd = clearErrCMD
 		} else {
@@ -98,6 +130,10 @@ func (m Model) Update(msg tea.Msg) (utils.Screen, tea.Cmd) {
 		m.err = nil
 		return m, nil
 	case filepicker.FileSelected:
+		if backup.IsDocumentPath(msg.Path) {
+			return m, m.diffCmd(msg.Path)
+		}
+
 		log.Println("Hey hi!!", msg.Path)
 		m.uploadingPath = msg.Path
 
@@ -108,11 +144,19 @@ func (m Model) Update(msg tea.Msg) (utils.Screen, tea.Cmd) {
 			}
 			defer f.Close()
 
-			err = m.api.UploadTSV(f)
+			err = m.api.UploadTSV(m.ctx, f)
 			return uploaded{err: err}
 		}, m.spin.Tick)
 	}
 
+	if m.importing != nil {
+		return m.updateImport(msg)
+	}
+
+	if msg, ok := msg.(tea.KeyPressMsg); ok && key.Matches(msg, KEY_EXPORT) && m.uploadingPath == "" {
+		return m, m.exportCmd()
+	}
+
 	if m.uploadingPath == "" {
 		fp, cmd := m.filepicker.Update(msg)
 		m.filepicker = fp
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/bank_data_tui/api"
+	"github.com/bank_data_tui/config"
+	"github.com/bank_data_tui/utils/notify"
+	"github.com/bank_data_tui/utils/session"
+	"github.com/charmbracelet/x/term"
+)
+
+const PASSPHRASE_ENV = "BANK_TUI_PASSPHRASE"
+
+// The session kept between runs. Does nothing when storing is off, or no passphrase was given
+type sessionStore struct {
+	cfg    config.Session
+	server string
+
+	passphrase string
+	// Set when the file couldn't be opened, so that it isn't overwritten with a different passphrase
+	locked bool
+	// What the current login used, kept for saving a refreshed token on exit
+	creds [2]string
+}
+
+// Takes the passphrase from the env, or asks for it on the terminal
+func openSessionStore(cfg *config.Config) *sessionStore {
+	s := &sessionStore{cfg: cfg.Session, server: cfg.Server}
+	if cfg.Session.Store == config.STORE_NONE {
+		return s
+	}
+
+	s.passphrase = os.Getenv(PASSPHRASE_ENV)
+	if s.passphrase == "" && term.IsTerminal(os.Stdin.Fd()) {
+		fmt.Fprint(os.Stderr, "Passphrase for the saved session (empty to skip): ")
+		p, err := term.ReadPassword(os.Stdin.Fd())
+		fmt.Fprintln(os.Stderr)
+		if err == nil {
+			s.passphrase = string(p)
+		}
+	}
+
+	return s
+}
+
+func (s *sessionStore) enabled() bool {
+	return s.passphrase != "" && !s.locked
+}
+
+// Logs c in with the saved session, reporting if there was one that worked
+func (s *sessionStore) restore(ctx context.Context, c *api.APIClient) (bool, error) {
+	if !s.enabled() {
+		return false, nil
+	}
+
+	d, err := session.Load(s.cfg.File, s.passphrase)
+	if err != nil {
+		s.locked = true
+		return false, fmt.Errorf("can't open the saved session: %w", err)
+	}
+	if d == nil || d.Server != s.server {
+		return false, nil
+	}
+
+	creds := [2]string{d.Username, d.Password}
+	if err := c.Resume(ctx, d.Token, creds); err != nil {
+		if err == api.ErrTokenExpired {
+			return false, nil
+		}
+		return false, err
+	}
+	s.creds = creds
+
+	return true, nil
+}
+
+// What to save, nil when nothing should be
+func (s *sessionStore) data(token string, creds [2]string) *session.Data {
+	if !s.enabled() || token == "" {
+		return nil
+	}
+
+	// The username is kept either way, so that an expired session can be resumed by the same user
+	d := &session.Data{Server: s.server, Token: token, Username: creds[0]}
+	if s.cfg.Store == config.STORE_PASSWORD {
+		d.Password = creds[1]
+	}
+
+	return d
+}
+
+// Saves in the background, deriving the key takes a moment
+func (s *sessionStore) saveCmd(token string, creds [2]string) tea.Cmd {
+	d := s.data(token, creds)
+	if d == nil {
+		return nil
+	}
+	s.creds = creds
+	file, passphrase := s.cfg.File, s.passphrase
+
+	return func() tea.Msg {
+		if err := session.Save(file, passphrase, d); err != nil {
+			return notify.Error(fmt.Errorf("can't save the session: %w", err), nil)
+		}
+
+		return nil
+	}
+}
+
+// Saves the token on exit, since it might've been refreshed
+func (s *sessionStore) close(c *api.APIClient) {
+	d := s.data(c.Token(), s.creds)
+	if d == nil {
+		return
+	}
+
+	if err := session.Save(s.cfg.File, s.passphrase, d); err != nil {
+		log.Println("Can't save the session:", err)
+	}
+}
+
+func (s *sessionStore) wipe() error {
+	s.creds = [2]string{}
+	if s.cfg.Store == config.STORE_NONE {
+		return nil
+	}
+	// A fresh file can be made with this run's passphrase
+	s.locked = false
+
+	return session.Wipe(s.cfg.File)
+}
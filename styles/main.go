@@ -8,37 +8,61 @@ import (
 )
 
 var (
-	COLOR_MAIN      = lipgloss.Color("#6557f9")
-	COLOR_SECONDARY = lipgloss.Color("#c36be3")
-	COLOR_WRONG     = lipgloss.ANSIColor(1)
-	COLOR_DISABLED  = lipgloss.ANSIColor(8)
+	COLOR_MAIN      color.Color
+	COLOR_SECONDARY color.Color
+	COLOR_WRONG     color.Color
+	COLOR_DISABLED  color.Color
 )
 
 var (
-	S_TEXT_DISABLED            = lipgloss.NewStyle().Foreground(COLOR_DISABLED)
-	S_TEXT_WRONG               = lipgloss.NewStyle().Foreground(COLOR_WRONG)
-	S_TEXT_NORMAL              = lipgloss.NewStyle().Foreground(lipgloss.NoColor{})
-	S_TEXT_HIGHLIGHT           = lipgloss.NewStyle().Foreground(COLOR_MAIN)
-	S_TEXT_HIGHLIGHT_SECONDARY = lipgloss.NewStyle().Foreground(COLOR_SECONDARY)
+	S_TEXT_DISABLED            lipgloss.Style
+	S_TEXT_WRONG               lipgloss.Style
+	S_TEXT_NORMAL              lipgloss.Style
+	S_TEXT_HIGHLIGHT           lipgloss.Style
+	S_TEXT_HIGHLIGHT_SECONDARY lipgloss.Style
+)
+
+var (
+	TI_CURSOR textinput.CursorStyle
 )
 
 var (
+	STYLE_FIELD lipgloss.Style
+	STYLE_BTN   lipgloss.Style
+
+	style_base_btn_selected     lipgloss.Style
+	STYLE_BTN_DISABLED          lipgloss.Style
+	STYLE_BTN_SELECTED          lipgloss.Style
+	STYLE_BTN_SELECTED_DISABLED lipgloss.Style
+	STYLE_BTN_SELECTED_BAD      lipgloss.Style
+)
+
+func init() {
+	SetTheme(DEFAULT_THEME)
+}
+
+// Rebuilds every style from the COLOR_ vars
+func build() {
+	S_TEXT_DISABLED = lipgloss.NewStyle().Foreground(COLOR_DISABLED)
+	S_TEXT_WRONG = lipgloss.NewStyle().Foreground(COLOR_WRONG)
+	S_TEXT_NORMAL = lipgloss.NewStyle().Foreground(lipgloss.NoColor{})
+	S_TEXT_HIGHLIGHT = lipgloss.NewStyle().Foreground(COLOR_MAIN)
+	S_TEXT_HIGHLIGHT_SECONDARY = lipgloss.NewStyle().Foreground(COLOR_SECONDARY)
+
 	TI_CURSOR = textinput.CursorStyle{
 		Color: COLOR_SECONDARY,
 		Blink: true,
 	}
-)
 
-var (
 	STYLE_FIELD = lipgloss.NewStyle().Padding(0, 1).Border(lipgloss.DoubleBorder()).Background(lipgloss.NoColor{})
-	STYLE_BTN   = STYLE_FIELD.Padding(1, 2)
+	STYLE_BTN = STYLE_FIELD.Padding(1, 2)
 
-	style_base_btn_selected     = STYLE_BTN.Foreground(lipgloss.NoColor{})
-	STYLE_BTN_DISABLED          = STYLE_BTN.Foreground(COLOR_DISABLED).BorderForeground(COLOR_DISABLED)
-	STYLE_BTN_SELECTED          = style_base_btn_selected.Background(COLOR_MAIN)
+	style_base_btn_selected = STYLE_BTN.Foreground(lipgloss.NoColor{})
+	STYLE_BTN_DISABLED = STYLE_BTN.Foreground(COLOR_DISABLED).BorderForeground(COLOR_DISABLED)
+	STYLE_BTN_SELECTED = style_base_btn_selected.Background(COLOR_MAIN)
 	STYLE_BTN_SELECTED_DISABLED = style_base_btn_selected.Background(COLOR_DISABLED).BorderForeground(COLOR_DISABLED)
-	STYLE_BTN_SELECTED_BAD      = style_base_btn_selected.Background(COLOR_WRONG)
-)
+	STYLE_BTN_SELECTED_BAD = style_base_btn_selected.Background(COLOR_WRONG)
+}
 
 func StyleBtn(disabled, selected, bad, small bool) lipgloss.Style {
 	style := STYLE_FIELD
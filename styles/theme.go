@@ -0,0 +1,90 @@
+package styles
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"regexp"
+	"strconv"
+
+	"charm.land/lipgloss/v2"
+)
+
+// Colors are either #rrggbb or an ANSI color number (0-255)
+type Theme struct {
+	Main      string `yaml:"main"`
+	Secondary string `yaml:"secondary"`
+	Wrong     string `yaml:"wrong"`
+	Disabled  string `yaml:"disabled"`
+}
+
+var DEFAULT_THEME = Theme{
+	Main:      "#6557f9",
+	Secondary: "#c36be3",
+	Wrong:     "1",
+	Disabled:  "8",
+}
+
+var hexColor = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+func parseColor(v string) (color.Color, error) {
+	if hexColor.MatchString(v) {
+		return lipgloss.Color(v), nil
+	}
+
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 || n > 255 {
+		return nil, fmt.Errorf("%q is neither #rrggbb nor 0-255", v)
+	}
+
+	return lipgloss.ANSIColor(n), nil
+}
+
+func (t Theme) colors() ([4]color.Color, error) {
+	res := [4]color.Color{}
+	errs := []error{}
+	for i, v := range [4]struct{ name, val string }{
+		{"main", t.Main},
+		{"secondary", t.Secondary},
+		{"wrong", t.Wrong},
+		{"disabled", t.Disabled},
+	} {
+		c, err := parseColor(v.val)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", v.name, err))
+		}
+		res[i] = c
+	}
+
+	return res, errors.Join(errs...)
+}
+
+func (t Theme) Validate() error {
+	_, err := t.colors()
+	return err
+}
+
+// Styles built out of the COLOR_ vars, that need rebuilding when the theme changes
+var themeHooks = []func(){}
+
+// Runs fn now & every time the theme changes. For packages that keep their own styles based on the COLOR_ vars
+func OnTheme(fn func()) {
+	themeHooks = append(themeHooks, fn)
+	fn()
+}
+
+// Swaps every color & rebuilds the styles. Needs to happen before anything is rendered
+func SetTheme(t Theme) error {
+	c, err := t.colors()
+	if err != nil {
+		return err
+	}
+
+	COLOR_MAIN, COLOR_SECONDARY, COLOR_WRONG, COLOR_DISABLED = c[0], c[1], c[2], c[3]
+	build()
+	for _, fn := range themeHooks {
+		fn()
+	}
+
+	return nil
+}
@@ -1,67 +1,207 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"log"
 
+	"charm.land/bubbles/v2/key"
 	tea "charm.land/bubbletea/v2"
+	"github.com/bank_data_tui/api"
+	"github.com/bank_data_tui/screens/budgets"
 	"github.com/bank_data_tui/screens/categories"
+	"github.com/bank_data_tui/screens/dashboard"
 	"github.com/bank_data_tui/screens/login"
 	"github.com/bank_data_tui/screens/mappings"
+	"github.com/bank_data_tui/screens/reports"
 	"github.com/bank_data_tui/screens/transactions"
 	"github.com/bank_data_tui/screens/upload"
 	"github.com/bank_data_tui/utils"
+	"github.com/bank_data_tui/utils/notify"
+	"github.com/bank_data_tui/utils/repo"
 )
 
+// A message from one of a screen's cmds, so that it gets back to that screen even if it's in the background by then
+type screenMsg struct {
+	s   Screen
+	msg tea.Msg
+}
+
+// Tags everything cmd results in with s. Batches are tagged one by one, so that they still run concurrently
+func tagCmd(s Screen, cmd tea.Cmd) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+
+	return func() tea.Msg {
+		switch msg := cmd().(type) {
+		case nil:
+			return nil
+		case tea.BatchMsg:
+			tagged := make(tea.BatchMsg, len(msg))
+			for i, c := range msg {
+				tagged[i] = tagCmd(s, c)
+			}
+			return tagged
+		case notify.Msg:
+			// Retries go back to the screen that failed
+			msg.Retry = tagCmd(s, msg.Retry)
+			return screenMsg{s: s, msg: msg}
+		default:
+			return screenMsg{s: s, msg: msg}
+		}
+	}
+}
+
+func (m *mainApp) updateScreen(s Screen, msg tea.Msg) tea.Cmd {
+	screen, cmd := m.screens[s].Update(msg)
+	m.screens[s] = screen
+
+	return tagCmd(s, cmd)
+}
+
 func (m *mainApp) switchToScreen(s Screen) tea.Cmd {
 	if m.curFocusedScreen == s {
 		return nil
 	}
-
 	m.curFocusedScreen = s
+
+	if screen, ok := m.screens[s]; ok {
+		if f, ok := screen.(utils.Focuser); ok {
+			return tagCmd(s, f.Focus())
+		}
+		return nil
+	}
+
+	h := m.height - HEADER_HEIGHT
 	switch s {
+	case S_DASHBOARD:
+		m.screens[s] = dashboard.New(m.screenCtx, m.api, m.cache, m.cfg, m.width, h)
+	case S_REPORTS:
+		m.screens[s] = reports.New(m.screenCtx, m.api, m.cache, m.cfg, m.width, h)
 	case S_TRANS:
-		m.screenImp = transactions.New(m.api, m.cache, m.width, m.height-HEADER_HEIGHT)
+		m.screens[s] = transactions.New(m.screenCtx, m.api, m.cache, m.cfg, m.width, h)
 	case S_MAPPINGS:
-		m.screenImp = mappings.New(m.api, m.cache, m.width, m.height-HEADER_HEIGHT)
+		m.screens[s] = mappings.New(m.screenCtx, m.api, m.cache, m.cfg, m.width, h)
 	case S_CATEGORIES:
-		m.screenImp = categories.New(m.api, m.cache, m.width, m.height-HEADER_HEIGHT)
+		m.screens[s] = categories.New(m.screenCtx, m.api, m.cache, m.cfg, m.width, h)
+	case S_BUDGETS:
+		m.screens[s] = budgets.New(m.screenCtx, m.api, m.cache, m.cfg, m.width, h)
 	case S_UPLOAD:
-		m.screenImp = upload.New(m.api, m.width, m.height-HEADER_HEIGHT)
+		m.screens[s] = upload.New(m.screenCtx, m.api, m.cache, m.width, h)
+	}
+
+	return tagCmd(s, m.screens[s].Init())
+}
+
+// Throws away every screen & everything cached for them, then goes back to a fresh login screen
+func (m *mainApp) dropScreens() tea.Cmd {
+	m.cancelScreens()
+	m.screenCtx, m.cancelScreens = context.WithCancel(m.ctx)
+
+	m.cache = &repo.Cache{}
+	m.screens = map[Screen]utils.Screen{S_LOGIN: login.NewScreenLogin()}
+	m.curFocusedScreen = S_LOGIN
+	m.expiredUser = ""
+
+	return tagCmd(S_LOGIN, m.screens[S_LOGIN].Init())
+}
+
+func (m *mainApp) logout() tea.Cmd {
+	m.api.Logout()
+	cmd := m.dropScreens()
+	if err := m.session.wipe(); err != nil {
+		return tea.Batch(cmd, notify.ErrorCmd(fmt.Errorf("can't remove the saved session: %w", err), nil))
 	}
 
-	return m.screenImp.Init()
+	return tea.Batch(cmd, notify.Info("Logged out"))
+}
+
+// Goes to a fresh login screen, keeping the other screens around in case the same user logs back in
+func (m *mainApp) sessionExpired(err error) tea.Cmd {
+	log.Println("Session expired:", err)
+
+	m.expiredUser = m.api.Username()
+	m.resumeScreen = m.curFocusedScreen
+	m.api.Logout()
+	m.help = helpOverlay{}
+
+	screen := login.NewScreenLogin()
+	screen.SetBanner("Session expired, log in again")
+	cmds := []tea.Cmd{screen.Init()}
+	if m.expiredUser != "" {
+		cmds = append(cmds, screen.SetUsername(m.expiredUser))
+	}
+
+	m.screens[S_LOGIN] = screen
+	m.curFocusedScreen = S_LOGIN
+
+	return tagCmd(S_LOGIN, tea.Batch(cmds...))
 }
 
 func (m *mainApp) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	var cmd tea.Cmd
 	batcher := []tea.Cmd{}
 
 	passToChildren := false
 
+	if cmd, ok := m.notify.Update(msg); ok {
+		return m, cmd
+	}
+
 	switch msg := msg.(type) {
+	case screenMsg:
+		if msg.s == m.curFocusedScreen {
+			return m.Update(msg.msg)
+		}
+
+		// Toasts from screens in the background still show up, & so do expired sessions
+		if cmd, ok := m.notify.Update(msg.msg); ok {
+			return m, cmd
+		}
+		if _, ok := msg.msg.(utils.MsgSessionExpired); ok {
+			return m.Update(msg.msg)
+		}
+		if _, ok := m.screens[msg.s]; ok {
+			batcher = append(batcher, m.updateScreen(msg.s, msg.msg))
+		}
 	case tea.KeyPressMsg:
-		switch msg.String() {
-		case "ctrl+c":
+		if m.help.open && !key.Matches(msg, KEY_QUIT) {
+			m.updateHelp(msg)
+			return m, nil
+		}
+
+		switch {
+		case key.Matches(msg, KEY_QUIT):
 			return m, tea.Quit
-		case "alt+tab":
+		case key.Matches(msg, KEY_HELP) && !m.typesText(msg):
+			m.help = helpOverlay{open: true}
+		case key.Matches(msg, KEY_LOGOUT) && m.curFocusedScreen != S_LOGIN:
+			batcher = append(batcher, m.logout())
+		case key.Matches(msg, KEY_NEXT_TAB):
 			s := m.curFocusedScreen + 1
 			if s > S_UPLOAD {
-				s = S_TRANS
+				s = S_DASHBOARD
 			}
 			batcher = append(batcher, m.switchToScreen(s))
-		case "alt+shift+tab":
+		case key.Matches(msg, KEY_PREV_TAB):
 			s := m.curFocusedScreen - 1
 			if s == S_LOGIN {
 				s = S_UPLOAD
 			}
 			batcher = append(batcher, m.switchToScreen(s))
-		case "alt+t":
+		case key.Matches(msg, KEY_TAB_DASHBOARD):
+			batcher = append(batcher, m.switchToScreen(S_DASHBOARD))
+		case key.Matches(msg, KEY_TAB_REPORTS):
+			batcher = append(batcher, m.switchToScreen(S_REPORTS))
+		case key.Matches(msg, KEY_TAB_TRANSACTIONS):
 			batcher = append(batcher, m.switchToScreen(S_TRANS))
-		case "alt+m":
+		case key.Matches(msg, KEY_TAB_MAPPINGS):
 			batcher = append(batcher, m.switchToScreen(S_MAPPINGS))
-		case "alt+c":
+		case key.Matches(msg, KEY_TAB_CATEGORIES):
 			batcher = append(batcher, m.switchToScreen(S_CATEGORIES))
-		case "alt+u", "alt+n":
+		case key.Matches(msg, KEY_TAB_BUDGETS):
+			batcher = append(batcher, m.switchToScreen(S_BUDGETS))
+		case key.Matches(msg, KEY_TAB_UPLOAD):
 			batcher = append(batcher, m.switchToScreen(S_UPLOAD))
 		default:
 			passToChildren = true
@@ -71,32 +211,50 @@ func (m *mainApp) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.height = msg.Height
 		m.width = msg.Width
 
-		m.screenImp, cmd = m.screenImp.Update(utils.ResizeMessage{
-			W: m.width,
-			H: m.height - HEADER_HEIGHT,
-		})
-		batcher = append(batcher, cmd)
+		for s := range m.screens {
+			batcher = append(batcher, m.updateScreen(s, utils.ResizeMessage{
+				W: m.width,
+				H: m.height - HEADER_HEIGHT,
+			}))
+		}
 	case login.LoginEntered:
-		screen, ok := m.screenImp.(*login.Model)
+		screen, ok := m.screens[S_LOGIN].(*login.Model)
 		if !ok {
 			panic("Somehow on the wrong model?")
 		}
 
-		err := m.api.Login(msg)
+		err := m.api.Login(m.ctx, msg)
 		if err != nil {
 			batcher = append(batcher, screen.WrongPassword())
+			if !api.IsAuthRejection(err) {
+				batcher = append(batcher, notify.ErrorCmd(err, func() tea.Msg { return msg }))
+			}
 		} else {
-			batcher = append(batcher, m.switchToScreen(S_TRANS))
+			batcher = append(batcher, m.session.saveCmd(m.api.Token(), msg))
+
+			next := m.homeScreen
+			if m.expiredUser != "" && m.expiredUser == msg[0] {
+				next = m.resumeScreen
+			} else if len(m.screens) > 1 {
+				// Someone else's screens (& cache) shouldn't be shown. The fresh login screen is left right away
+				m.dropScreens()
+			}
+			m.expiredUser = ""
+			batcher = append(batcher, m.switchToScreen(next))
+		}
+	case utils.MsgSessionExpired:
+		// Requests that were already running fail the same way, once is enough
+		if m.curFocusedScreen != S_LOGIN {
+			batcher = append(batcher, m.sessionExpired(msg.Err))
 		}
 	case utils.MsgGoToHome:
-		batcher = append(batcher, m.switchToScreen(S_TRANS))
+		batcher = append(batcher, m.switchToScreen(m.homeScreen))
 	default:
 		passToChildren = true
 	}
 
 	if passToChildren {
-		m.screenImp, cmd = m.screenImp.Update(msg)
-		batcher = append(batcher, cmd)
+		batcher = append(batcher, m.updateScreen(m.curFocusedScreen, msg))
 	}
 
 	return m, tea.Batch(batcher...)
@@ -0,0 +1,182 @@
+// Export & import of mappings and categories, so they can be backed up or moved between servers
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/bank_data_tui/api"
+	"gopkg.in/yaml.v3"
+)
+
+// Bump this when the document changes in a way older versions can't read
+const VERSION = 1
+
+type Format string
+
+const (
+	FORMAT_YAML Format = "yaml"
+	FORMAT_JSON Format = "json"
+)
+
+// Guesses the format from a file extension, defaulting to yaml
+func FormatFromPath(p string) Format {
+	if strings.EqualFold(filepath.Ext(p), ".json") {
+		return FORMAT_JSON
+	}
+
+	return FORMAT_YAML
+}
+
+// Whether p looks like an export document
+func IsDocumentPath(p string) bool {
+	switch strings.ToLower(filepath.Ext(p)) {
+	case ".yaml", ".yml", ".json":
+		return true
+	}
+
+	return false
+}
+
+type Category struct {
+	Name  string `json:"name" yaml:"name"`
+	Color string `json:"color" yaml:"color"`
+	Icon  string `json:"icon" yaml:"icon"`
+}
+
+// Same as api.Mapping, but with the category referenced by name, since IDs differ between servers
+type Mapping struct {
+	Name     string `json:"name" yaml:"name"`
+	Priority int    `json:"priority" yaml:"priority"`
+
+	InpText string   `json:"inputText,omitempty" yaml:"inputText,omitempty"`
+	InpAmt  *float64 `json:"inputAmount,omitempty" yaml:"inputAmount,omitempty"`
+
+	ResName     string `json:"resName,omitempty" yaml:"resName,omitempty"`
+	ResCategory string `json:"resCategory,omitempty" yaml:"resCategory,omitempty"`
+}
+
+type Document struct {
+	Version    int         `json:"version" yaml:"version"`
+	Categories []*Category `json:"categories" yaml:"categories"`
+	Mappings   []*Mapping  `json:"mappings" yaml:"mappings"`
+}
+
+// Builds a document out of everything currently on the server
+func Export(ctx context.Context, c *api.APIClient) (*Document, error) {
+	cats, err := c.CategoriesFetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	maps, err := c.MappingsFetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return fromAPI(cats, maps)
+}
+
+func fromAPI(cats []*api.Category, maps []*api.Mapping) (*Document, error) {
+	doc := &Document{
+		Version:    VERSION,
+		Categories: make([]*Category, len(cats)),
+		Mappings:   make([]*Mapping, len(maps)),
+	}
+
+	names := map[string]string{}
+	for i, v := range cats {
+		names[v.ID] = v.Name
+		doc.Categories[i] = &Category{Name: v.Name, Color: v.Color, Icon: v.Icon}
+	}
+
+	for i, v := range maps {
+		cat := ""
+		if v.ResCategoryID != "" {
+			var ok bool
+			if cat, ok = names[v.ResCategoryID]; !ok {
+				return nil, fmt.Errorf("mapping %q uses a category that doesn't exist (%s)", v.Name, v.ResCategoryID)
+			}
+		}
+
+		doc.Mappings[i] = &Mapping{
+			Name:        v.Name,
+			Priority:    v.Priority,
+			InpText:     v.InpText,
+			InpAmt:      v.InpAmt,
+			ResName:     v.ResName,
+			ResCategory: cat,
+		}
+	}
+
+	slices.SortFunc(doc.Categories, func(a, b *Category) int { return strings.Compare(a.Name, b.Name) })
+	slices.SortStableFunc(doc.Mappings, func(a, b *Mapping) int { return b.Priority - a.Priority })
+
+	return doc, nil
+}
+
+func (d *Document) Encode(f Format) ([]byte, error) {
+	if f == FORMAT_JSON {
+		return json.MarshalIndent(d, "", "  ")
+	}
+
+	return yaml.Marshal(d)
+}
+
+func Decode(data []byte, f Format) (*Document, error) {
+	doc := &Document{}
+
+	var err error
+	if f == FORMAT_JSON {
+		err = json.Unmarshal(data, doc)
+	} else {
+		err = yaml.Unmarshal(data, doc)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return doc, doc.Validate()
+}
+
+// Checks the things the server can't, since they only matter across the whole document
+func (d *Document) Validate() error {
+	if d.Version == 0 {
+		return errors.New("missing version, is this an export document?")
+	}
+	if d.Version > VERSION {
+		return fmt.Errorf("document is version %d, but only up to %d is supported", d.Version, VERSION)
+	}
+
+	cats := map[string]bool{}
+	for _, v := range d.Categories {
+		if v.Name == "" {
+			return errors.New("category without a name")
+		}
+		if cats[v.Name] {
+			return fmt.Errorf("category %q is listed twice", v.Name)
+		}
+		cats[v.Name] = true
+	}
+
+	maps := map[string]bool{}
+	for _, v := range d.Mappings {
+		if v.Name == "" {
+			return errors.New("mapping without a name")
+		}
+		if maps[v.Name] {
+			return fmt.Errorf("mapping %q is listed twice", v.Name)
+		}
+		maps[v.Name] = true
+
+		if v.ResCategory != "" && !cats[v.ResCategory] {
+			return fmt.Errorf("mapping %q uses category %q, which isn't in the document", v.Name, v.ResCategory)
+		}
+	}
+
+	return nil
+}
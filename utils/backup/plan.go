@@ -0,0 +1,293 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/bank_data_tui/api"
+)
+
+type Action int
+
+const (
+	ACT_CREATE Action = iota
+	ACT_UPDATE
+	ACT_DELETE
+)
+
+type Change struct {
+	Action Action
+	// Exactly one of these is set. For deletes, it is built from the server's version
+	Category *Category
+	Mapping  *Mapping
+	// Server ID, empty for creates
+	ID string
+	// What changed, for updates. Eg. `priority: 2 → 5`
+	Diffs []string
+	// Deletes one of several on the server named like something in the document. Skipped unless pruning
+	Duplicate bool
+	// Moves a mapping off a Duplicate category onto the one that's kept. Skipped unless pruning, like the delete
+	Repoint bool
+}
+
+func (c Change) Name() string {
+	if c.Category != nil {
+		return c.Category.Name
+	}
+
+	return c.Mapping.Name
+}
+
+// Everything needed to make the server match a document
+type Plan struct {
+	Changes []Change
+	// Also applies the Duplicate deletes
+	Prune bool
+	// Category IDs on the server by name, filled in as categories are created
+	catIDs map[string]string
+}
+
+func (p *Plan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+func (p *Plan) skipped(c Change) bool {
+	return (c.Duplicate || c.Repoint) && !p.Prune
+}
+
+// How many duplicates there are to prune
+func (p *Plan) Duplicates() int {
+	n := 0
+	for _, c := range p.Changes {
+		if c.Duplicate {
+			n++
+		}
+	}
+
+	return n
+}
+
+// Counts of creates, updates & deletes that would be applied
+func (p *Plan) Summary() (created, updated, deleted int) {
+	for _, c := range p.Changes {
+		if p.skipped(c) {
+			continue
+		}
+
+		switch c.Action {
+		case ACT_CREATE:
+			created++
+		case ACT_UPDATE:
+			updated++
+		case ACT_DELETE:
+			deleted++
+		}
+	}
+
+	return
+}
+
+// Compares doc to the server. Categories & mappings are matched by name, anything missing from doc gets deleted.
+// When the server has a name more than once, the first one is matched & the rest are only deleted with Prune (after
+// moving mappings off the categories)
+func Diff(ctx context.Context, c *api.APIClient, doc *Document) (*Plan, error) {
+	cats, err := c.CategoriesFetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	maps, err := c.MappingsFetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return diff(cats, maps, doc), nil
+}
+
+func diff(cats []*api.Category, maps []*api.Mapping, doc *Document) *Plan {
+	p := &Plan{catIDs: map[string]string{}}
+	catNames := map[string]string{}
+	wantCats, wantMaps := map[string]bool{}, map[string]bool{}
+	for _, v := range doc.Categories {
+		wantCats[v.Name] = true
+	}
+	for _, v := range doc.Mappings {
+		wantMaps[v.Name] = true
+	}
+
+	// Deletes are last, since mappings might still point at a deleted category until they're updated
+	catDeletes := []Change{}
+	serverCats := map[string]*api.Category{}
+	// IDs of the Duplicate categories
+	pruned := map[string]bool{}
+	for _, v := range cats {
+		catNames[v.ID] = v.Name
+		if _, dupe := serverCats[v.Name]; dupe {
+			catDeletes = append(catDeletes, Change{
+				Action: ACT_DELETE, ID: v.ID, Category: &Category{Name: v.Name, Color: v.Color, Icon: v.Icon},
+				Duplicate: wantCats[v.Name],
+			})
+			pruned[v.ID] = wantCats[v.Name]
+			continue
+		}
+		serverCats[v.Name] = v
+		p.catIDs[v.Name] = v.ID
+	}
+
+	for _, want := range doc.Categories {
+		have, ok := serverCats[want.Name]
+		if !ok {
+			p.Changes = append(p.Changes, Change{Action: ACT_CREATE, Category: want})
+			continue
+		}
+		delete(serverCats, want.Name)
+
+		diffs := []string{}
+		diffs = diffField(diffs, "color", have.Color, want.Color)
+		diffs = diffField(diffs, "icon", have.Icon, want.Icon)
+		if len(diffs) != 0 {
+			p.Changes = append(p.Changes, Change{Action: ACT_UPDATE, ID: have.ID, Category: want, Diffs: diffs})
+		}
+	}
+	for _, v := range cats {
+		if serverCats[v.Name] == v {
+			catDeletes = append(catDeletes, Change{Action: ACT_DELETE, ID: v.ID, Category: &Category{Name: v.Name, Color: v.Color, Icon: v.Icon}})
+		}
+	}
+
+	mapDeletes := []Change{}
+	serverMaps := map[string]*api.Mapping{}
+	for _, v := range maps {
+		if _, dupe := serverMaps[v.Name]; dupe {
+			mapDeletes = append(mapDeletes, Change{
+				Action: ACT_DELETE, ID: v.ID, Mapping: &Mapping{Name: v.Name, Priority: v.Priority},
+				Duplicate: wantMaps[v.Name],
+			})
+			continue
+		}
+		serverMaps[v.Name] = v
+	}
+
+	for _, want := range doc.Mappings {
+		have, ok := serverMaps[want.Name]
+		if !ok {
+			p.Changes = append(p.Changes, Change{Action: ACT_CREATE, Mapping: want})
+			continue
+		}
+		delete(serverMaps, want.Name)
+
+		diffs := []string{}
+		diffs = diffField(diffs, "priority", strconv.Itoa(have.Priority), strconv.Itoa(want.Priority))
+		diffs = diffField(diffs, "regex", have.InpText, want.InpText)
+		diffs = diffField(diffs, "amount", fmtAmt(have.InpAmt), fmtAmt(want.InpAmt))
+		diffs = diffField(diffs, "name", have.ResName, want.ResName)
+		diffs = diffField(diffs, "category", catNames[have.ResCategoryID], want.ResCategory)
+		switch {
+		case len(diffs) != 0:
+			// Saved with the category by name, so it ends up on the kept one either way
+			p.Changes = append(p.Changes, Change{Action: ACT_UPDATE, ID: have.ID, Mapping: want, Diffs: diffs})
+		case pruned[have.ResCategoryID]:
+			// Same name, but pruning deletes the category it's on
+			p.Changes = append(p.Changes, Change{
+				Action: ACT_UPDATE, ID: have.ID, Mapping: want, Repoint: true,
+				Diffs: []string{"category: moved off a duplicate " + quoteOrNone(want.ResCategory)},
+			})
+		}
+	}
+	for _, v := range maps {
+		if serverMaps[v.Name] == v {
+			mapDeletes = append(mapDeletes, Change{Action: ACT_DELETE, ID: v.ID, Mapping: &Mapping{Name: v.Name, Priority: v.Priority}})
+		}
+	}
+
+	p.Changes = append(p.Changes, mapDeletes...)
+	p.Changes = append(p.Changes, catDeletes...)
+
+	return p
+}
+
+func diffField(diffs []string, name, have, want string) []string {
+	if have == want {
+		return diffs
+	}
+
+	return append(diffs, name+": "+quoteOrNone(have)+" → "+quoteOrNone(want))
+}
+
+func quoteOrNone(v string) string {
+	if v == "" {
+		return "none"
+	}
+
+	return strconv.Quote(v)
+}
+
+func fmtAmt(v *float64) string {
+	if v == nil {
+		return ""
+	}
+
+	return strconv.FormatFloat(*v, 'f', -1, 64)
+}
+
+// Runs every change in order (but the duplicates, unless pruning). On error, done is how many changes went
+// through. Diffing again & applying that is safe, so that's the way to retry
+func (p *Plan) Apply(ctx context.Context, c *api.APIClient, noRetroactive bool) (done int, err error) {
+	for _, ch := range p.Changes {
+		if p.skipped(ch) {
+			continue
+		}
+		if err := p.apply(ctx, c, ch, noRetroactive); err != nil {
+			return done, fmt.Errorf("%s: %w", ch.Name(), err)
+		}
+		done++
+	}
+
+	return done, nil
+}
+
+func (p *Plan) apply(ctx context.Context, c *api.APIClient, ch Change, noRetroactive bool) error {
+	if ch.Category != nil {
+		cat := &api.SavableCategory{Name: ch.Category.Name, Color: ch.Category.Color, Icon: ch.Category.Icon}
+
+		switch ch.Action {
+		case ACT_CREATE:
+			id, err := c.CategoriesCreate(ctx, cat)
+			if err != nil {
+				return err
+			}
+			p.catIDs[cat.Name] = id
+			return nil
+		case ACT_UPDATE:
+			return c.CategoriesUpdate(ctx, ch.ID, cat)
+		default:
+			return c.CategoriesDelete(ctx, ch.ID)
+		}
+	}
+
+	if ch.Action == ACT_DELETE {
+		return c.MappingsDelete(ctx, ch.ID, noRetroactive)
+	}
+
+	m := &api.Mapping{
+		Name:     ch.Mapping.Name,
+		Priority: ch.Mapping.Priority,
+		InpText:  ch.Mapping.InpText,
+		InpAmt:   ch.Mapping.InpAmt,
+		ResName:  ch.Mapping.ResName,
+	}
+	if ch.Mapping.ResCategory != "" {
+		id, ok := p.catIDs[ch.Mapping.ResCategory]
+		if !ok {
+			return fmt.Errorf("category %q doesn't exist", ch.Mapping.ResCategory)
+		}
+		m.ResCategoryID = id
+	}
+
+	if ch.Action == ACT_CREATE {
+		_, err := c.MappingsCreate(ctx, m, noRetroactive)
+		return err
+	}
+
+	return c.MappingsUpdate(ctx, ch.ID, m, noRetroactive)
+}
@@ -0,0 +1,84 @@
+package backup
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/bank_data_tui/api"
+)
+
+func TestDiffDuplicates(t *testing.T) {
+	cats := []*api.Category{
+		{ID: "1", SavableCategory: api.SavableCategory{Name: "Groceries"}},
+		{ID: "2", SavableCategory: api.SavableCategory{Name: "Groceries"}},
+		{ID: "3", SavableCategory: api.SavableCategory{Name: "Old"}},
+		{ID: "4", SavableCategory: api.SavableCategory{Name: "Old"}},
+	}
+	maps := []*api.Mapping{
+		{ID: "5", Name: "Shop", Priority: 1},
+		{ID: "6", Name: "Shop", Priority: 1},
+	}
+	doc := &Document{
+		Categories: []*Category{{Name: "Groceries"}},
+		Mappings:   []*Mapping{{Name: "Shop", Priority: 1}},
+	}
+
+	p := diff(cats, maps, doc)
+	if n := p.Duplicates(); n != 2 {
+		t.Errorf("duplicates = %d, want 2", n)
+	}
+	// Old isn't in doc, so both of those go either way
+	if _, _, d := p.Summary(); d != 2 {
+		t.Errorf("deletes = %d, want 2", d)
+	}
+
+	p.Prune = true
+	if _, _, d := p.Summary(); d != 4 {
+		t.Errorf("deletes when pruning = %d, want 4", d)
+	}
+	for _, c := range p.Changes {
+		if c.Duplicate && c.ID != "2" && c.ID != "6" {
+			t.Errorf("the first of a name should be kept, not %s", c.ID)
+		}
+	}
+}
+
+func TestDiffRepointsOffDuplicates(t *testing.T) {
+	cats := []*api.Category{
+		{ID: "1", SavableCategory: api.SavableCategory{Name: "Food"}},
+		{ID: "2", SavableCategory: api.SavableCategory{Name: "Food"}},
+	}
+	maps := []*api.Mapping{
+		{ID: "3", Name: "Lunch", Priority: 1, ResCategoryID: "2"},
+		{ID: "4", Name: "Dinner", Priority: 1, ResCategoryID: "1"},
+	}
+	doc := &Document{
+		Categories: []*Category{{Name: "Food"}},
+		Mappings: []*Mapping{
+			{Name: "Lunch", Priority: 1, ResCategory: "Food"},
+			{Name: "Dinner", Priority: 1, ResCategory: "Food"},
+		},
+	}
+
+	p := diff(cats, maps, doc)
+	if c, u, d := p.Summary(); c != 0 || u != 0 || d != 0 {
+		t.Errorf("without pruning = %d, %d, %d, want nothing to do", c, u, d)
+	}
+
+	p.Prune = true
+	if _, u, d := p.Summary(); u != 1 || d != 1 {
+		t.Errorf("when pruning = %d updates & %d deletes, want 1 & 1", u, d)
+	}
+
+	i := slices.IndexFunc(p.Changes, func(c Change) bool { return c.Action == ACT_UPDATE })
+	if i == -1 {
+		t.Fatal("no update for the mapping on the duplicate")
+	}
+	// Apply saves it with the category by name, which is the kept one
+	if ch := p.Changes[i]; ch.ID != "3" || ch.Mapping.ResCategory != "Food" || p.catIDs["Food"] != "1" {
+		t.Errorf("update %+v doesn't move Lunch onto category 1", ch)
+	}
+	if j := slices.IndexFunc(p.Changes, func(c Change) bool { return c.Action == ACT_DELETE }); j < i {
+		t.Error("the duplicate is deleted before the mapping is moved off it")
+	}
+}
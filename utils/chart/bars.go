@@ -0,0 +1,87 @@
+package chart
+
+import (
+	"image/color"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+	"github.com/bank_data_tui/styles"
+	"github.com/bank_data_tui/utils"
+)
+
+const (
+	// Narrower than this, bars are left out & only the labels & values are shown
+	MIN_BAR_WIDTH = 4
+	// Labels get at most this share of the width when there's bars
+	MAX_LABEL_SHARE = 0.4
+)
+
+type Bar struct {
+	// Can be styled
+	Label string
+	Value float64
+	Color color.Color
+}
+
+// A bar w cells wide at most, as long as v is of top. Always w wide
+func HBar(w int, v, top float64, c color.Color) string {
+	bar, rest := hbar(w, v, top)
+	return lipgloss.NewStyle().Foreground(c).Render(bar) + strings.Repeat(" ", rest)
+}
+
+// Like HBar, but the rest of w is a dimmed track, so that it reads as a share of the whole
+func Progress(w int, v, top float64, c color.Color) string {
+	bar, rest := hbar(w, v, top)
+	return lipgloss.NewStyle().Foreground(c).Render(bar) + styles.S_TEXT_DISABLED.Render(strings.Repeat("─", rest))
+}
+
+// The bar itself & how many cells of w are left after it
+func hbar(w int, v, top float64) (string, int) {
+	if w <= 0 {
+		return "", 0
+	}
+
+	e := eighths(v, top, w)
+	bar := strings.Repeat(H_BLOCKS[7], e/8)
+	if e%8 != 0 {
+		bar += H_BLOCKS[e%8-1]
+	}
+
+	return bar, w - (e+7)/8
+}
+
+// A line per bar with its label, the bar & its value (as format writes it), scaled to the biggest value. Fills
+// w, dropping the bars when they wouldn't fit
+func Bars(w int, bars []Bar, format func(float64) string) string {
+	if len(bars) == 0 {
+		return ""
+	}
+
+	labelW, valueW := 0, 0
+	top := 0.0
+	values := make([]string, len(bars))
+	for i, b := range bars {
+		values[i] = format(b.Value)
+		labelW = max(labelW, lipgloss.Width(b.Label))
+		valueW = max(valueW, lipgloss.Width(values[i]))
+		top = max(top, b.Value)
+	}
+
+	labelW = min(labelW, int(float64(w)*MAX_LABEL_SHARE))
+	// Spaces on either side of the bar
+	barW := w - labelW - valueW - 2
+
+	lines := make([]string, len(bars))
+	for i, b := range bars {
+		if barW < MIN_BAR_WIDTH {
+			lines[i] = utils.JoinHorizontal2(w, utils.Overflow(b.Label, w-valueW-1), values[i])
+			continue
+		}
+
+		label := lipgloss.NewStyle().Width(labelW).Render(utils.Overflow(b.Label, labelW))
+		value := lipgloss.PlaceHorizontal(valueW, lipgloss.Right, values[i])
+		lines[i] = label + " " + HBar(barW, b.Value, top, b.Color) + " " + value
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,61 @@
+// Bar charts & sparklines drawn with block characters, for the dashboard & reports. Everything here is plain
+// strings of the size asked for, so they can be joined like any other view
+package chart
+
+import (
+	"image/color"
+	"strconv"
+
+	"charm.land/lipgloss/v2"
+	"github.com/bank_data_tui/api"
+	"github.com/bank_data_tui/styles"
+)
+
+// Eighths of a cell, filled from the left & from the bottom. Smallest first, so [n-1] is n eighths
+var (
+	H_BLOCKS = []string{"▏", "▎", "▍", "▌", "▋", "▊", "▉", "█"}
+	V_BLOCKS = []string{"▁", "▂", "▃", "▄", "▅", "▆", "▇", "█"}
+)
+
+// What a category is drawn in. Ones without a colour (or without a category) are dimmed, as uncategorised
+// spending is everywhere else
+func CategoryColor(c *api.Category) color.Color {
+	if c == nil {
+		return styles.COLOR_DISABLED
+	}
+	if _, err := strconv.ParseUint(c.Color, 16, 32); err != nil || len(c.Color) != 6 {
+		return styles.COLOR_DISABLED
+	}
+
+	return lipgloss.Color("#" + c.Color)
+}
+
+// For category names, in their colour. Ones without a valid colour are left as they are
+func CategoryStyle(c *api.Category) lipgloss.Style {
+	style := lipgloss.NewStyle()
+	if c == nil {
+		return style.Foreground(styles.COLOR_DISABLED)
+	}
+	if _, err := strconv.ParseUint(c.Color, 16, 32); err == nil && len(c.Color) == 6 {
+		style = style.Foreground(lipgloss.Color("#" + c.Color))
+	}
+
+	return style
+}
+
+// Instead of a chart that doesn't fit, the same as the app does for the whole screen
+func TooSmall(w, h int) string {
+	box := lipgloss.NewStyle().Width(w).Height(h).MaxWidth(w).MaxHeight(h).Align(lipgloss.Center, lipgloss.Center)
+	return box.Render(styles.S_TEXT_DISABLED.Render("Too Small"))
+}
+
+// How many eighths of n cells v is, out of top. Anything above 0 is at least an eighth, so it doesn't disappear,
+// but it never takes more than the n cells
+func eighths(v, top float64, n int) int {
+	if v <= 0 || top <= 0 || n <= 0 {
+		return 0
+	}
+
+	e := int(min(v/top, 1)*float64(n*8) + 0.5)
+	return min(max(e, 1), n*8)
+}
@@ -0,0 +1,128 @@
+package chart
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/bank_data_tui/api"
+	"github.com/bank_data_tui/utils/screentest"
+	"github.com/charmbracelet/x/ansi"
+)
+
+var CATEGORIES = []*api.Category{
+	{ID: "1", SavableCategory: api.SavableCategory{Name: "Groceries", Color: "4caf50", Icon: "🛒"}},
+	{ID: "2", SavableCategory: api.SavableCategory{Name: "Transport", Color: "2196f3", Icon: "🚆"}},
+	{ID: "3", SavableCategory: api.SavableCategory{Name: "Eating out", Color: "ff9800", Icon: "🍔"}},
+	// Not a colour, drawn dimmed
+	{ID: "4", SavableCategory: api.SavableCategory{Name: "Fun", Color: "nope", Icon: "🎮"}},
+}
+
+func format(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
+
+func bars() []Bar {
+	vals := []float64{123.99, 60.89, 30.57, 0.5}
+	res := make([]Bar, len(CATEGORIES))
+	for i, c := range CATEGORIES {
+		res[i] = Bar{Label: CategoryStyle(c).Render(c.Icon + " " + c.Name), Value: vals[i], Color: CategoryColor(c)}
+	}
+
+	return res
+}
+
+func columns() []Column {
+	res := []Column{}
+	for i := range 12 {
+		col := Column{Label: fmt.Sprintf("M%d", i+1)}
+		for j, c := range CATEGORIES {
+			// Made up, but different in every column
+			col.Segments = append(col.Segments, Segment{Value: float64((i*7+j*13)%20 + j), Color: CategoryColor(c)})
+		}
+		res = append(res, col)
+	}
+	// Nothing spent, the column stays empty
+	res[3].Segments = nil
+
+	return res
+}
+
+func TestBars(t *testing.T) {
+	for _, w := range []int{20, 30, 50, 80} {
+		t.Run(strconv.Itoa(w), func(t *testing.T) {
+			screentest.Golden(t, t.Name(), ansi.Strip(Bars(w, bars(), format)))
+		})
+	}
+
+	screentest.Golden(t, t.Name()+".ansi", Bars(50, bars(), format))
+}
+
+func TestSparkline(t *testing.T) {
+	vals := []float64{0, 1, 2, 3, 4, 5, 6, 7, 8, 0, 4, 8}
+	for _, w := range []int{-1, 0, 5, 12, 20} {
+		t.Run(strconv.Itoa(w), func(t *testing.T) {
+			screentest.Golden(t, t.Name(), ansi.Strip(Sparkline(w, vals, CategoryColor(CATEGORIES[0]))))
+		})
+	}
+}
+
+func TestProgress(t *testing.T) {
+	for _, v := range []float64{0, 0.1, 42, 100, 150} {
+		t.Run(strconv.FormatFloat(v, 'f', -1, 64), func(t *testing.T) {
+			screentest.Golden(t, t.Name(), ansi.Strip(Progress(20, v, 100, CategoryColor(CATEGORIES[0]))))
+		})
+	}
+}
+
+// Whatever's left for a bar can be nothing, or less once the labels are taken out
+func TestBarWidths(t *testing.T) {
+	for _, w := range []int{-3, 0, 1} {
+		for _, v := range []float64{0, 0.1, 100, 150} {
+			t.Run(fmt.Sprintf("%d/%v", w, v), func(t *testing.T) {
+				want := max(w, 0)
+				if got := ansi.StringWidth(HBar(w, v, 100, CategoryColor(CATEGORIES[0]))); got != want {
+					t.Errorf("HBar is %d wide, want %d", got, want)
+				}
+				if got := ansi.StringWidth(Progress(w, v, 100, CategoryColor(CATEGORIES[0]))); got != want {
+					t.Errorf("Progress is %d wide, want %d", got, want)
+				}
+			})
+		}
+	}
+}
+
+func TestEighths(t *testing.T) {
+	for _, c := range []struct {
+		v, top float64
+		n      int
+		want   int
+	}{
+		{0, 100, 4, 0},
+		{-5, 100, 4, 0},
+		{5, 0, 4, 0},
+		{50, 100, 4, 16},
+		{100, 100, 4, 32},
+		// Over the top is still only n cells
+		{150, 100, 4, 32},
+		// Tiny is still an eighth
+		{0.001, 100, 4, 1},
+		{0.001, 100, 0, 0},
+		{100, 100, 0, 0},
+		{100, 100, -2, 0},
+	} {
+		if got := eighths(c.v, c.top, c.n); got != c.want {
+			t.Errorf("eighths(%v, %v, %d) = %d, want %d", c.v, c.top, c.n, got, c.want)
+		}
+	}
+}
+
+func TestStacked(t *testing.T) {
+	for _, s := range [][2]int{{10, 5}, {30, 6}, {50, 10}, {80, 15}, {5, 1}} {
+		t.Run(fmt.Sprintf("%dx%d", s[0], s[1]), func(t *testing.T) {
+			screentest.Golden(t, t.Name(), ansi.Strip(Stacked(s[0], s[1], columns())))
+		})
+	}
+
+	screentest.Golden(t, t.Name()+".ansi", Stacked(30, 6, columns()))
+}
@@ -0,0 +1,31 @@
+package chart
+
+import (
+	"image/color"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+)
+
+// A cell per value, as tall as it is of the biggest one. Nothing (or less) is a space. Only the last w values are
+// shown when there's more, so put the newest last
+func Sparkline(w int, vals []float64, c color.Color) string {
+	w = max(w, 0)
+	vals = vals[max(len(vals)-w, 0):]
+
+	top := 0.0
+	for _, v := range vals {
+		top = max(top, v)
+	}
+
+	var b strings.Builder
+	for _, v := range vals {
+		if e := eighths(v, top, 1); e == 0 {
+			b.WriteString(" ")
+		} else {
+			b.WriteString(V_BLOCKS[e-1])
+		}
+	}
+
+	return lipgloss.NewStyle().Foreground(c).Render(b.String())
+}
@@ -0,0 +1,144 @@
+package chart
+
+import (
+	"image/color"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+)
+
+const (
+	// Columns don't get wider than this, however much room there is
+	MAX_COL_WIDTH = 6
+	COL_GAP       = 1
+)
+
+type Segment struct {
+	Value float64
+	Color color.Color
+}
+
+type Column struct {
+	// Shown under the column, cut to its width (at a space if there's one). Can be styled
+	Label string
+	// Bottom first
+	Segments []Segment
+}
+
+// How many of n columns fit in w, the rest being left out
+func Fits(w, n int) int {
+	return max(min(n, (w+COL_GAP)/(1+COL_GAP)), 0)
+}
+
+// Width of each column when n of them are in w
+func colWidth(w, n int) int {
+	if n == 0 {
+		return 0
+	}
+
+	return max(min((w+COL_GAP)/n-COL_GAP, MAX_COL_WIDTH), 1)
+}
+
+// Column of segments, each column's height being its total against the biggest one, with the labels on the last
+// line. w by h, or TooSmall when there isn't room for a column & its label. Only the last columns that fit are
+// shown, so put the newest last
+func Stacked(w, h int, cols []Column) string {
+	n := Fits(w, len(cols))
+	if h < 2 || n == 0 {
+		return TooSmall(w, h)
+	}
+	cols = cols[len(cols)-n:]
+
+	colW := colWidth(w, n)
+	barH := h - 1
+
+	top := 0.0
+	for _, c := range cols {
+		top = max(top, total(c))
+	}
+
+	// Drawn a column at a time, top line first
+	drawn := make([][]string, n)
+	for i, c := range cols {
+		drawn[i] = drawColumn(c, colW, barH, top)
+	}
+
+	lines := make([]string, h)
+	for y := range barH {
+		parts := make([]string, n)
+		for i := range cols {
+			parts[i] = drawn[i][y]
+		}
+		lines[y] = strings.Join(parts, strings.Repeat(" ", COL_GAP))
+	}
+
+	labels := make([]string, n)
+	for i, c := range cols {
+		labels[i] = lipgloss.PlaceHorizontal(colW, lipgloss.Center, cut(c.Label, colW))
+	}
+	lines[barH] = strings.Join(labels, strings.Repeat(" ", COL_GAP))
+
+	return lipgloss.NewStyle().Width(w).Render(strings.Join(lines, "\n"))
+}
+
+// s in w, without half a word at the end. ie. "Jan 24" in 5 is "Jan"
+func cut(s string, w int) string {
+	if ansi.StringWidth(s) <= w {
+		return s
+	}
+
+	res := ansi.Truncate(s, w, "")
+	plain := ansi.Strip(res)
+	if i := strings.LastIndex(plain, " "); i > 0 {
+		return ansi.Truncate(res, ansi.StringWidth(plain[:i]), "")
+	}
+
+	return res
+}
+
+func total(c Column) float64 {
+	t := 0.0
+	for _, s := range c.Segments {
+		t += max(s.Value, 0)
+	}
+
+	return t
+}
+
+// The lines of one column, top first. Each cell is in the colour of the segment that covers most of it
+func drawColumn(c Column, w, h int, top float64) []string {
+	height := eighths(total(c), top, h)
+
+	// Where each segment ends, in eighths from the bottom
+	ends := make([]int, len(c.Segments))
+	sum := 0.0
+	for i, s := range c.Segments {
+		sum += max(s.Value, 0)
+		ends[i] = eighths(sum, top, h)
+	}
+	colorAt := func(e int) color.Color {
+		for i, end := range ends {
+			if e < end {
+				return c.Segments[i].Color
+			}
+		}
+		return lipgloss.NoColor{}
+	}
+
+	res := make([]string, h)
+	for y := range h {
+		// Eighths below & filled in this line
+		below := (h - 1 - y) * 8
+		filled := min(height-below, 8)
+		if filled <= 0 {
+			res[y] = strings.Repeat(" ", w)
+			continue
+		}
+
+		style := lipgloss.NewStyle().Foreground(colorAt(below + filled/2))
+		res[y] = style.Render(strings.Repeat(V_BLOCKS[filled-1], w))
+	}
+
+	return res
+}
@@ -0,0 +1,45 @@
+package editor
+
+import (
+	"charm.land/bubbles/v2/key"
+	"github.com/bank_data_tui/utils/keymap"
+)
+
+var (
+	KEY_NEXT  = key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "next field"))
+	KEY_PREV  = key.NewBinding(key.WithKeys("shift+tab"), key.WithHelp("shift+tab", "previous field"))
+	KEY_UP    = key.NewBinding(key.WithKeys("up"), key.WithHelp("↑", "field above"))
+	KEY_DOWN  = key.NewBinding(key.WithKeys("down"), key.WithHelp("↓", "field below"))
+	KEY_LEFT  = key.NewBinding(key.WithKeys("left"), key.WithHelp("←", "field to the left"))
+	KEY_RIGHT = key.NewBinding(key.WithKeys("right"), key.WithHelp("→", "field to the right"))
+	KEY_PRESS = key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "next field / press button"))
+	// Same as KEY_PRESS, but saves & deletes don't touch old transactions
+	KEY_PRESS_ALT = key.NewBinding(key.WithKeys("alt+enter"), key.WithHelp("alt+enter", "press button, not retroactively"))
+
+	KEY_NEXT_SUGGESTION = key.NewBinding(key.WithKeys("ctrl+n"), key.WithHelp("ctrl+n", "next suggestion"))
+	KEY_PREV_SUGGESTION = key.NewBinding(key.WithKeys("ctrl+p"), key.WithHelp("ctrl+p", "previous suggestion"))
+)
+
+func init() {
+	keymap.Register("editor", map[string]*key.Binding{
+		"next":            &KEY_NEXT,
+		"prev":            &KEY_PREV,
+		"up":              &KEY_UP,
+		"down":            &KEY_DOWN,
+		"left":            &KEY_LEFT,
+		"right":           &KEY_RIGHT,
+		"press":           &KEY_PRESS,
+		"press_alt":       &KEY_PRESS_ALT,
+		"next_suggestion": &KEY_NEXT_SUGGESTION,
+		"prev_suggestion": &KEY_PREV_SUGGESTION,
+	})
+}
+
+func (c *Model) KeyHelp() []key.Binding {
+	return keymap.Group("editor")
+}
+
+// If a text field is focused
+func (c *Model) Typing() bool {
+	return len(c.inpFields) != 0 && !c.inButtons(c.focusedField)
+}
@@ -5,12 +5,14 @@ import (
 	"fmt"
 	"slices"
 
+	"charm.land/bubbles/v2/key"
 	"charm.land/bubbles/v2/textinput"
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
 	"github.com/bank_data_tui/api"
 	"github.com/bank_data_tui/styles"
 	"github.com/bank_data_tui/utils"
+	"github.com/bank_data_tui/utils/notify"
 )
 
 type DataField struct {
@@ -37,6 +39,8 @@ type Model struct {
 	dataFields   []*DataField
 	inpFields    []textinput.Model
 	layout       [][]int
+	// What each field had when it was last loaded from (or saved to) its data field, to tell what's been typed
+	loaded []string
 
 	popupVisible bool
 	popupOnNo    bool
@@ -100,8 +104,8 @@ func New(
 			Cursor: styles.TI_CURSOR,
 		})
 		f.Placeholder = d.Title
-		f.KeyMap.NextSuggestion.SetKeys("ctrl+n")
-		f.KeyMap.PrevSuggestion.SetKeys("ctrl+p")
+		f.KeyMap.NextSuggestion = KEY_NEXT_SUGGESTION
+		f.KeyMap.PrevSuggestion = KEY_PREV_SUGGESTION
 
 		inpFields[i] = f
 
@@ -134,12 +138,10 @@ func New(
 		m(ptr)
 	}
 
+	loaded := make([]string, len(dataFields))
 	for i, f := range dataFields {
-		if f.Value == nil {
-			inpFields[i].SetValue(f.GetValue())
-		} else {
-			inpFields[i].SetValue(*f.Value)
-		}
+		loaded[i] = f.get()
+		inpFields[i].SetValue(loaded[i])
 	}
 
 	for i, f := range inpFields {
@@ -153,6 +155,7 @@ func New(
 		ItemID:     id,
 		dataFields: dataFields,
 		inpFields:  inpFields,
+		loaded:     loaded,
 		create:     createFunc,
 		update:     updateFunc,
 		layout:     layout,
@@ -165,6 +168,36 @@ func New(
 	return m
 }
 
+func (d *DataField) get() string {
+	if d.Value == nil {
+		return d.GetValue()
+	}
+
+	return *d.Value
+}
+
+// Re-reads the data fields that changed behind the editor's back, leaving whatever was typed into the others
+func (c *Model) Refresh() {
+	for i, d := range c.dataFields {
+		if c.inpFields[i].Value() != c.loaded[i] {
+			continue
+		}
+
+		c.loaded[i] = d.get()
+		c.inpFields[i].SetValue(c.loaded[i])
+	}
+}
+
+// Current (unsaved) value of the i-th data field
+func (c *Model) FieldValue(i int) string {
+	return c.inpFields[i].Value()
+}
+
+// Current validation error of the i-th data field
+func (c *Model) FieldErr(i int) error {
+	return c.inpFields[i].Err
+}
+
 func (c *Model) Init() tea.Cmd {
 	cmd := c.inpFields[0].Focus()
 
@@ -206,8 +239,8 @@ func (c *Model) Update(msg tea.Msg) (*Model, tea.Cmd) {
 			passToChildren = false
 		}
 
-		switch msg.String() {
-		case "tab", "right", "down", "left", "shift+tab", "up":
+		switch {
+		case key.Matches(msg, KEY_NEXT, KEY_PREV, KEY_UP, KEY_DOWN, KEY_LEFT, KEY_RIGHT):
 			passToChildren = false
 
 			if c.popupVisible {
@@ -215,38 +248,31 @@ func (c *Model) Update(msg tea.Msg) (*Model, tea.Cmd) {
 				break
 			}
 
-			handled, nf := c.handleNavKey(msg.String())
+			handled, nf := c.handleNavKey(msg)
 			if !handled {
 				passToChildren = true
 			} else {
 				batcher = append(batcher, c.focusField(nf))
 			}
-		case "enter", "alt":
+		case key.Matches(msg, KEY_PRESS, KEY_PRESS_ALT):
 			passToChildren = false
+			alt := key.Matches(msg, KEY_PRESS_ALT)
 			switch c.focusedField {
 			case BTN_SAVE:
 				// save
-				batcher = append(batcher, c.handleSaveEnter(msg.Mod.Contains(tea.ModAlt)))
+				batcher = append(batcher, c.handleSaveEnter(alt))
 			case BTN_DEL:
 				// delete
-				err := c.del(msg.Mod.Contains(tea.ModAlt), c.ItemID)
-				if err != nil {
-					// TODO: Better error handling lmao
-					panic("Can't delete: " + err.Error())
-				}
-				batcher = append(batcher, func() tea.Msg { return ItemDel(c.ItemID) })
+				batcher = append(batcher, c.delCmd(alt))
 			case BTN_RESET:
 				// reset
 				c.focusField(c.layout[0][0])
 				for i, d := range c.dataFields {
-					if d.Value == nil {
-						c.inpFields[i].SetValue(d.GetValue())
-					} else {
-						c.inpFields[i].SetValue(*d.Value)
-					}
+					c.loaded[i] = d.get()
+					c.inpFields[i].SetValue(c.loaded[i])
 				}
 			default:
-				_, nf := c.handleNavKey("enter")
+				nf := c.navKeyHorizontal(1)
 
 				batcher = append(batcher, c.focusField(nf))
 			}
@@ -299,6 +325,10 @@ func (c *Model) SetWidth(w int) {
 		// fuck you textinput component >:(
 		availWidth := w - len(row) + 1 - len(row)
 		flexers := 0
+		if len(row) == 1 {
+			// Alone in its row, the title goes next to the field (& a space after it)
+			availWidth -= lipgloss.Width(c.dataFields[row[0]].Title) + 1
+		}
 
 		for _, i := range row {
 			f := c.dataFields[i]
@@ -347,8 +377,13 @@ func (c *Model) handleSaveEnter(alt bool) tea.Cmd {
 		} else {
 			*d.Value = f.Value()
 		}
+		c.loaded[i] = f.Value()
 	}
 
+	return c.saveCmd(alt)
+}
+
+func (c *Model) saveCmd(alt bool) tea.Cmd {
 	return func() tea.Msg {
 		msg, err := c.save(alt)
 		if err == nil {
@@ -356,13 +391,24 @@ func (c *Model) handleSaveEnter(alt bool) tea.Cmd {
 		}
 
 		if e, ok := err.(*api.ValidationErr); !ok {
-			panic(err)
+			return notify.Error(err, c.saveCmd(alt))
 		} else {
 			return validationErrMsg(e.Details)
 		}
 	}
 }
 
+func (c *Model) delCmd(alt bool) tea.Cmd {
+	return func() tea.Msg {
+		err := c.del(alt, c.ItemID)
+		if err != nil {
+			return notify.Error(err, c.delCmd(alt))
+		}
+
+		return ItemDel(c.ItemID)
+	}
+}
+
 const (
 	BTN_SAVE  = -1
 	BTN_DEL   = -2
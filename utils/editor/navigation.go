@@ -4,6 +4,7 @@ import (
 	"slices"
 	"strings"
 
+	"charm.land/bubbles/v2/key"
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
 )
@@ -31,9 +32,9 @@ func (c Model) inButtons(i int) bool {
 }
 
 // returns "handled", "new focus id"
-func (c Model) handleNavKey(key string) (bool, int) {
-	switch key {
-	case "tab":
+func (c Model) handleNavKey(msg tea.KeyPressMsg) (bool, int) {
+	switch {
+	case key.Matches(msg, KEY_NEXT):
 		if !c.inButtons(c.focusedField) {
 			sug := c.inpFields[c.focusedField].CurrentSuggestion()
 			if sug != "" && !strings.EqualFold(c.inpFields[c.focusedField].Value(), sug) {
@@ -42,17 +43,15 @@ func (c Model) handleNavKey(key string) (bool, int) {
 		}
 
 		return true, c.navKeyHorizontal(1)
-	case "enter":
-		return true, c.navKeyHorizontal(1)
-	case "shift+tab":
+	case key.Matches(msg, KEY_PREV):
 		return true, c.navKeyHorizontal(-1)
-	case "down":
+	case key.Matches(msg, KEY_DOWN):
 		return true, c.navKeyVertical(1)
-	case "up":
+	case key.Matches(msg, KEY_UP):
 		return true, c.navKeyVertical(-1)
-	case "right":
+	case key.Matches(msg, KEY_RIGHT):
 		return c.navKeyHorizontalTextConflict(1)
-	case "left":
+	case key.Matches(msg, KEY_LEFT):
 		return c.navKeyHorizontalTextConflict(-1)
 	}
 
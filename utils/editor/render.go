@@ -56,7 +56,12 @@ func (c Model) View() (string, *tea.Cursor) {
 
 			res, offsets := utils.JoinHorizontalEqualSpread(c.width, parts...)
 			if cursorPart != -1 {
-				cur.X += offsets[cursorPart]
+				if offsets == nil {
+					// Too narrow for the row, so it isn't shown
+					cur = nil
+				} else {
+					cur.X += offsets[cursorPart]
+				}
 			}
 
 			sections = append(sections, res)
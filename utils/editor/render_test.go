@@ -0,0 +1,72 @@
+package editor
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/bank_data_tui/utils/screentest"
+)
+
+var WIDTHS = []int{40, 60, 100}
+
+// Same layout as the mappings' editor: a wide & a narrow field on the first 2 rows, then a wide one
+func newEditor(w int, id string, vals *[5]string) *Model {
+	return New(
+		w, id,
+		[]*DataField{
+			{Title: "Name", ID: "name", Value: &vals[0], Row: 0, Flex: true},
+			{Title: "Priority", ID: "priority", Value: &vals[1], Row: 0, Col: 1},
+			{Title: "Match Description Regex", ID: "inpText", Value: &vals[2], Row: 1, Flex: true},
+			{Title: "Match Amount", ID: "inpAmt", Value: &vals[3], Row: 1, Col: 1},
+			{Title: "Resulting Name", ID: "resName", Value: &vals[4], Row: 2},
+		},
+		func(bool) (string, error) { return "new", nil },
+		func(bool, string) error { return nil },
+		func(bool, string) error { return nil },
+		RequireFields(0),
+		AddIntValidator(1),
+		AddFloatValidator(3),
+	)
+}
+
+func newHarness(t *testing.T, w int, id string, vals [5]string) *screentest.Harness[*Model] {
+	m := newEditor(w, id, &vals)
+	return screentest.New(t, m, m.Init()).Settle()
+}
+
+func widths(t *testing.T, f func(t *testing.T, w int)) {
+	for _, w := range WIDTHS {
+		t.Run(strconv.Itoa(w), func(t *testing.T) { f(t, w) })
+	}
+}
+
+func TestViewNew(t *testing.T) {
+	widths(t, func(t *testing.T, w int) {
+		sh := newHarness(t, w, "", [5]string{})
+		sh.Golden(t.Name() + "_empty")
+
+		sh.Type("Groceries").Keys("tab").Type("3").Keys("tab").Type("^TESCO").Keys("tab").Type("-4.5")
+		sh.Golden(t.Name() + "_typed")
+
+		sh.Keys("tab", "tab")
+		sh.Golden(t.Name() + "_buttons")
+	})
+}
+
+// Invalid values straight away, & a value wider than its field
+func TestViewExisting(t *testing.T) {
+	widths(t, func(t *testing.T, w int) {
+		sh := newHarness(t, w, "some-id", [5]string{"Rent", "x", "", "1e", "A rather long resulting name that won't fit"})
+		sh.Golden(t.Name())
+
+		sh.Keys("shift+tab")
+		sh.Golden(t.Name() + "_buttons")
+	})
+}
+
+func TestViewResize(t *testing.T) {
+	sh := newHarness(t, 100, "", [5]string{"Groceries", "3"})
+	sh.Keys("tab")
+	sh.M.SetWidth(45)
+	sh.Golden(t.Name())
+}
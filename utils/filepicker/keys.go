@@ -3,12 +3,56 @@ package filepicker
 import (
 	"strings"
 
+	"charm.land/bubbles/v2/key"
 	tea "charm.land/bubbletea/v2"
+	"github.com/bank_data_tui/utils/keymap"
 )
 
-func (m *Model) handleKeyCommon(key tea.KeyPressMsg) (tea.Cmd, bool) {
-	switch k := key.String(); k {
-	case "alt+up":
+var (
+	KEY_PARENT       = key.NewBinding(key.WithKeys("alt+up"), key.WithHelp("alt+↑", "parent dir"))
+	KEY_TOGGLE_INPUT = key.NewBinding(key.WithKeys("shift+up", "shift+down", "shift+tab"), key.WithHelp("shift+tab", "switch between path & files"))
+
+	// While typing a path
+	KEY_INPUT_UP       = key.NewBinding(key.WithKeys("up"), key.WithHelp("↑", "previous suggestion"))
+	KEY_INPUT_DOWN     = key.NewBinding(key.WithKeys("down"), key.WithHelp("↓", "next suggestion"))
+	KEY_INPUT_COMPLETE = key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "open suggestion"))
+	KEY_INPUT_SELECT   = key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "select file / open suggestion"))
+
+	// While in the file list
+	KEY_PICKER_UP          = key.NewBinding(key.WithKeys("up"), key.WithHelp("↑", "previous file"))
+	KEY_PICKER_DOWN        = key.NewBinding(key.WithKeys("down"), key.WithHelp("↓", "next file"))
+	KEY_PICKER_TOGGLE      = key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "back to path"))
+	KEY_PICKER_SELECT      = key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "select file / open dir"))
+	KEY_PICKER_SCROLL_UP   = key.NewBinding(key.WithKeys("ctrl+up"), key.WithHelp("ctrl+↑", "scroll up"))
+	KEY_PICKER_SCROLL_DOWN = key.NewBinding(key.WithKeys("ctrl+down"), key.WithHelp("ctrl+↓", "scroll down"))
+)
+
+func init() {
+	keymap.Register("filepicker", map[string]*key.Binding{
+		"parent":       &KEY_PARENT,
+		"toggle_input": &KEY_TOGGLE_INPUT,
+	})
+	keymap.Register("filepicker.input", map[string]*key.Binding{
+		"up":       &KEY_INPUT_UP,
+		"down":     &KEY_INPUT_DOWN,
+		"complete": &KEY_INPUT_COMPLETE,
+		"select":   &KEY_INPUT_SELECT,
+	})
+	keymap.Register("filepicker.picker", map[string]*key.Binding{
+		"up":          &KEY_PICKER_UP,
+		"down":        &KEY_PICKER_DOWN,
+		"toggle":      &KEY_PICKER_TOGGLE,
+		"select":      &KEY_PICKER_SELECT,
+		"scroll_up":   &KEY_PICKER_SCROLL_UP,
+		"scroll_down": &KEY_PICKER_SCROLL_DOWN,
+	})
+	keymap.Related("filepicker", "filepicker.input")
+	keymap.Related("filepicker", "filepicker.picker")
+}
+
+func (m *Model) handleKeyCommon(msg tea.KeyPressMsg) (tea.Cmd, bool) {
+	switch {
+	case key.Matches(msg, KEY_PARENT):
 		if m.acceptedPath == "/" {
 			return nil, true
 		}
@@ -20,23 +64,23 @@ func (m *Model) handleKeyCommon(key tea.KeyPressMsg) (tea.Cmd, bool) {
 		}
 		cmd := m.forceUserSel(np)
 		return cmd, true
-	case "shift+up", "shift+down", "shift+tab":
+	case key.Matches(msg, KEY_TOGGLE_INPUT):
 		return m.toggleInput(), true
 	}
 
 	return nil, false
 }
 
-func (m *Model) handleKeyTextinput(key tea.KeyPressMsg) (tea.Cmd, bool) {
-	switch k := key.String(); k {
-	case "up", "down":
+func (m *Model) handleKeyTextinput(msg tea.KeyPressMsg) (tea.Cmd, bool) {
+	switch {
+	case key.Matches(msg, KEY_INPUT_UP, KEY_INPUT_DOWN):
 		if m.sugIndex != -1 {
 			sugs := m.suggestions()
-			m.sugIndex = cycle(m.sugIndex, len(sugs), k == "down")
+			m.sugIndex = cycle(m.sugIndex, len(sugs), key.Matches(msg, KEY_INPUT_DOWN))
 		}
 		return nil, true
-	case "tab", "enter":
-		if k == "enter" && m.inpIsFile {
+	case key.Matches(msg, KEY_INPUT_COMPLETE, KEY_INPUT_SELECT):
+		if key.Matches(msg, KEY_INPUT_SELECT) && m.inpIsFile {
 			return func() tea.Msg {
 				return FileSelected{m.currentCleanInput()}
 			}, true
@@ -53,21 +97,21 @@ func (m *Model) handleKeyTextinput(key tea.KeyPressMsg) (tea.Cmd, bool) {
 	return nil, false
 }
 
-func (m *Model) handleKeyPicker(key tea.KeyPressMsg) (tea.Cmd, bool) {
-	switch k := key.String(); k {
-	case "up", "down":
+func (m *Model) handleKeyPicker(msg tea.KeyPressMsg) (tea.Cmd, bool) {
+	switch {
+	case key.Matches(msg, KEY_PICKER_UP, KEY_PICKER_DOWN):
 		if m.fileIndex != -1 {
 			m.fileIndex = cycle(
 				m.fileIndex,
 				len(m.visibleEntries(m.dirs))+len(m.visibleEntries(m.files)),
-				k == "down",
+				key.Matches(msg, KEY_PICKER_DOWN),
 			)
 			m.adjustVP()
 		}
 		return nil, true
-	case "tab", "shift+tab":
+	case key.Matches(msg, KEY_PICKER_TOGGLE):
 		return m.toggleInput(), true
-	case "enter":
+	case key.Matches(msg, KEY_PICKER_SELECT):
 		dirs, files := m.visibleEntries(m.dirs), m.visibleEntries(m.files)
 		if m.fileIndex == -1 {
 			return nil, true
@@ -78,13 +122,27 @@ func (m *Model) handleKeyPicker(key tea.KeyPressMsg) (tea.Cmd, bool) {
 		return func() tea.Msg {
 			return FileSelected{m.acceptedPath + "/" + files[m.fileIndex-len(dirs)].Name()}
 		}, true
-	case "ctrl+down":
+	case key.Matches(msg, KEY_PICKER_SCROLL_DOWN):
 		m.vpOffset++
 		m.adjustVP()
-	case "ctrl+up":
+	case key.Matches(msg, KEY_PICKER_SCROLL_UP):
 		m.vpOffset--
 		m.adjustVP()
 	}
 
 	return nil, false
 }
+
+func (m Model) KeyHelp() []key.Binding {
+	mode := "filepicker.picker"
+	if m.textField.Focused() {
+		mode = "filepicker.input"
+	}
+
+	return append(keymap.Group("filepicker"), keymap.Group(mode)...)
+}
+
+// If the path is being typed in
+func (m Model) Typing() bool {
+	return m.textField.Focused()
+}
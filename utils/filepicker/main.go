@@ -130,6 +130,11 @@ func (m *Model) SetSize(w, h int) {
 	m.textField.CharLimit = int(float64(w-4) * 0.75)
 }
 
+// The directory currently being browsed
+func (m Model) Dir() string {
+	return m.acceptedPath
+}
+
 func (m Model) currentCleanInput() string {
 	return m.cleanPath(m.textField.Value())
 }
@@ -0,0 +1,58 @@
+package filepicker
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/bank_data_tui/utils/screentest"
+)
+
+// Tall enough for the whole dir, & small enough that it has to scroll
+var SIZES = [][2]int{{30, 8}, {60, 12}, {100, 20}}
+
+// Starts in testdata/home/project, with testdata/home as ~ so that the path shown is the same everywhere
+func newHarness(t *testing.T, w, h int) *screentest.Harness[Model] {
+	home, err := filepath.Abs(filepath.Join("testdata", "home"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := New(w, h, []string{"tsv", "csv", "yaml", "yml", "json"})
+	m.home = home
+
+	return screentest.New(t, m, readDirCMD(filepath.Join(home, "project"), true)).Settle()
+}
+
+func TestView(t *testing.T) {
+	screentest.Sizes(t, SIZES, func(t *testing.T, w, h int) {
+		sh := newHarness(t, w, h)
+		sh.Golden(t.Name())
+
+		sh.Keys("shift+tab", "down", "down", "down")
+		sh.Golden(t.Name() + "_picker")
+
+		sh.Keys("up", "up", "enter").Settle()
+		sh.Golden(t.Name() + "_subdir")
+
+		sh.Keys("down", "down", "down", "down", "down", "down")
+		sh.Golden(t.Name() + "_bottom")
+
+		sh.Keys("ctrl+up", "ctrl+up")
+		sh.Golden(t.Name() + "_scrolled")
+	})
+}
+
+func TestViewInput(t *testing.T) {
+	screentest.Sizes(t, SIZES, func(t *testing.T, w, h int) {
+		sh := newHarness(t, w, h)
+
+		sh.Type("st").Settle()
+		sh.Golden(t.Name() + "_suggestion")
+
+		sh.Keys("tab").Settle()
+		sh.Golden(t.Name() + "_completed")
+
+		sh.Type("nope").Settle()
+		sh.Golden(t.Name() + "_missing")
+	})
+}
@@ -0,0 +1,131 @@
+// Every remappable key binding, by name. Packages register their bindings in init, and the config
+// overrides them (by group.name) before any screen is created
+package keymap
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"charm.land/bubbles/v2/key"
+)
+
+// Group that is active everywhere, so it can't share keys with anything
+const GROUP_GLOBAL = "global"
+
+type Entry struct {
+	Group string
+	Name  string
+	B     *key.Binding
+}
+
+func (e Entry) ID() string {
+	return e.Group + "." + e.Name
+}
+
+var (
+	entries = []Entry{}
+	// Groups that take keys at the same time, so they can't share keys
+	related = map[[2]string]bool{}
+)
+
+// Registers bindings under group. The config refers to them as group.name
+func Register(group string, bs map[string]*key.Binding) {
+	names := make([]string, 0, len(bs))
+	for n := range bs {
+		names = append(names, n)
+	}
+	slices.Sort(names)
+
+	for _, n := range names {
+		entries = append(entries, Entry{Group: group, Name: n, B: bs[n]})
+	}
+}
+
+// Marks groups as active at the same time (ie. a screen & the widgets it embeds)
+func Related(groups ...string) {
+	for _, a := range groups {
+		for _, b := range groups {
+			related[[2]string{a, b}] = true
+		}
+	}
+}
+
+func conflicts(a, b string) bool {
+	return a == b || a == GROUP_GLOBAL || b == GROUP_GLOBAL || related[[2]string{a, b}]
+}
+
+// Every registered binding, in registration order
+func All() []Entry {
+	return entries
+}
+
+// Bindings of a single group
+func Group(group string) []key.Binding {
+	res := []key.Binding{}
+	for _, e := range entries {
+		if e.Group == group {
+			res = append(res, *e.B)
+		}
+	}
+
+	return res
+}
+
+// Group a registered binding (or a copy of it) is in, "" if it isn't registered
+func GroupOf(b key.Binding) string {
+	for _, e := range entries {
+		if e.B.Help() == b.Help() && slices.Equal(e.B.Keys(), b.Keys()) {
+			return e.Group
+		}
+	}
+
+	return ""
+}
+
+// Replaces the keys of the bindings in overrides (group.name -> keys), then checks for conflicts
+func Apply(overrides map[string][]string) error {
+	errs := []error{}
+
+	ids := make([]string, 0, len(overrides))
+	for id := range overrides {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	for _, id := range ids {
+		i := slices.IndexFunc(entries, func(e Entry) bool { return e.ID() == id })
+		if i == -1 {
+			errs = append(errs, fmt.Errorf("keys.%s: unknown action", id))
+			continue
+		}
+
+		keys := overrides[id]
+		b := entries[i].B
+		b.SetKeys(keys...)
+		b.SetHelp(strings.Join(keys, "/"), b.Help().Desc)
+	}
+
+	return errors.Join(append(errs, Check())...)
+}
+
+// Reports keys used by more than one binding in groups that are active together
+func Check() error {
+	errs := []error{}
+	for i, a := range entries {
+		for _, b := range entries[i+1:] {
+			if !conflicts(a.Group, b.Group) {
+				continue
+			}
+
+			for _, k := range a.B.Keys() {
+				if slices.Contains(b.B.Keys(), k) {
+					errs = append(errs, fmt.Errorf("keys: %s is used by both %s and %s", k, a.ID(), b.ID()))
+				}
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
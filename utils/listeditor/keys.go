@@ -4,6 +4,8 @@ import (
 	"charm.land/bubbles/v2/key"
 	"charm.land/bubbles/v2/list"
 	tea "charm.land/bubbletea/v2"
+	"github.com/bank_data_tui/utils"
+	"github.com/bank_data_tui/utils/keymap"
 )
 
 var listKeyMap = list.KeyMap{
@@ -51,6 +53,25 @@ var listKeyMap = list.KeyMap{
 	),
 }
 
+func init() {
+	keymap.Register("listeditor", map[string]*key.Binding{
+		"up":           &listKeyMap.CursorUp,
+		"down":         &listKeyMap.CursorDown,
+		"prev_page":    &listKeyMap.PrevPage,
+		"next_page":    &listKeyMap.NextPage,
+		"start":        &listKeyMap.GoToStart,
+		"end":          &listKeyMap.GoToEnd,
+		"filter":       &listKeyMap.Filter,
+		"clear_filter": &listKeyMap.ClearFilter,
+	})
+	// Only active while typing a filter, when the editor doesn't get keys
+	keymap.Register("listeditor.filter", map[string]*key.Binding{
+		"cancel": &listKeyMap.CancelWhileFiltering,
+		"accept": &listKeyMap.AcceptWhileFiltering,
+	})
+	keymap.Related("listeditor", "editor")
+}
+
 func doesKeyMatchList(k tea.KeyPressMsg, l list.Model) bool {
 	if l.FilterState() == list.Filtering {
 		return key.Matches(
@@ -75,3 +96,25 @@ func doesKeyMatchList(k tea.KeyPressMsg, l list.Model) bool {
 		l.KeyMap.Filter,
 	)
 }
+
+func (m Model[T, PT]) KeyHelp() []key.Binding {
+	if m.list.FilterState() == list.Filtering {
+		return keymap.Group("listeditor.filter")
+	}
+
+	res := []key.Binding{}
+	if a, ok := m.Abstraction.(utils.KeyHelper); ok {
+		res = append(res, a.KeyHelp()...)
+	}
+	// Side panels get every key while open
+	if a, ok := m.Abstraction.(SidePanel); ok && a.SidePanelOpen() {
+		return res
+	}
+
+	res = append(res, keymap.Group("listeditor")...)
+	return append(res, m.editor.KeyHelp()...)
+}
+
+func (m Model[T, PT]) Typing() bool {
+	return m.list.FilterState() == list.Filtering || m.editor.Typing()
+}
@@ -1,9 +1,13 @@
 package listeditor
 
 import (
+	"context"
+
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
+	"github.com/bank_data_tui/styles"
 	"github.com/bank_data_tui/utils/editor"
+	"github.com/bank_data_tui/utils/notify"
 	"charm.land/bubbles/v2/list"
 	"charm.land/bubbles/v2/spinner"
 )
@@ -19,8 +23,35 @@ var (
 )
 
 type Abstraction[T any] interface {
-	NewEditor(w, h int, curItem T) *editor.Model
-	InitialFetch() ([]T, error)
+	NewEditor(ctx context.Context, w, h int, curItem T) *editor.Model
+	InitialFetch(ctx context.Context) ([]T, error)
+}
+
+// Optional, lets the abstraction take keys before the list & editor get them. cur is the selected item
+type KeyHandler[T any] interface {
+	HandleKey(msg tea.KeyPressMsg, cur T) (tea.Cmd, bool)
+}
+
+// Optional, keeps the list ordered. Same semantics as slices.SortFunc
+type Sorter[T any] interface {
+	Compare(a, b T) int
+}
+
+// Optional, lets the abstraction show something else in place of the editor
+type SidePanel interface {
+	SidePanelOpen() bool
+	SidePanel(w, h int) string
+}
+
+// Optional, can stop the refetch when the screen is shown again, ie. while there are unsaved changes
+type FocusGuard interface {
+	RefreshOnFocus() bool
+}
+
+// Optional, told about the editor whenever it may have changed, for keeping what's worked out from its fields
+// out of View
+type EditorWatcher interface {
+	EditorUpdated(e *editor.Model)
 }
 
 type Item interface {
@@ -35,6 +66,9 @@ type Model[item any, PT interface {
 }] struct {
 	Abstraction[PT]
 
+	// Cancelled when the screen is closed
+	ctx context.Context
+
 	list     list.Model
 	spin     spinner.Model
 	isLoaded bool
@@ -55,9 +89,10 @@ func (ni NewItem) FilterValue() string { return string(ni) }
 func New[T any, PT interface {
 	Item
 	*T
-}](newItemText string, delegate list.ItemDelegate, w, h int) *Model[T, PT] {
+}](ctx context.Context, newItemText string, delegate list.ItemDelegate, w, h int) *Model[T, PT] {
 	m := &Model[T, PT]{
-		spin:     spinner.Model{},
+		ctx:      ctx,
+		spin:     spinner.New(spinner.WithStyle(styles.S_TEXT_HIGHLIGHT)),
 		isLoaded: false,
 		newItem:  NewItem(newItemText),
 		items:    []PT{},
@@ -77,40 +112,90 @@ func New[T any, PT interface {
 
 type initialResp[T any] []T
 
+// Refetches the items, keeping the selection & whatever is in the editor
+func (m *Model[T, PT]) Focus() tea.Cmd {
+	// Also fetches when nothing loaded yet, a first fetch that failed on an expired session has no retry
+	if a, ok := m.Abstraction.(FocusGuard); m.isLoaded && ok && !a.RefreshOnFocus() {
+		return nil
+	}
+
+	batcher := []tea.Cmd{m.initialFetch()}
+	if a, ok := m.Abstraction.(interface {
+		Init(ctx context.Context) tea.Cmd
+	}); ok {
+		batcher = append(batcher, a.Init(m.ctx))
+	}
+
+	return tea.Batch(batcher...)
+}
+
 func (m *Model[T, PT]) Init() tea.Cmd {
 	m.resetEditor()
+	m.editorUpdated()
 
 	batcher := []tea.Cmd{
-		func() tea.Msg {
-			res, err := m.InitialFetch()
-			if err != nil {
-				panic("Can't do initial fetch: " + err.Error())
-			}
-
-			return initialResp[PT](res)
-		},
+		m.initialFetch(),
 		m.spin.Tick,
 		m.editor.Init(),
 	}
 
-	if a, ok := m.Abstraction.(interface{ Init() tea.Cmd }); ok {
-		batcher = append(batcher, a.Init())
+	if a, ok := m.Abstraction.(interface {
+		Init(ctx context.Context) tea.Cmd
+	}); ok {
+		batcher = append(batcher, a.Init(m.ctx))
 	}
 
 	return tea.Batch(batcher...)
 }
 
+func (m *Model[T, PT]) initialFetch() tea.Cmd {
+	return func() tea.Msg {
+		res, err := m.InitialFetch(m.ctx)
+		if err != nil {
+			return notify.Error(err, m.initialFetch())
+		}
+
+		return initialResp[PT](res)
+	}
+}
+
+func (m *Model[T, PT]) editorUpdated() {
+	if a, ok := m.Abstraction.(EditorWatcher); ok {
+		a.EditorUpdated(m.editor)
+	}
+}
+
 func (m Model[T, PT]) View() (string, *tea.Cursor) {
 	if !m.isLoaded {
 		return m.spin.View(), nil
 	}
 
 	l := m.list.View()
+
+	if a, ok := m.Abstraction.(SidePanel); ok && a.SidePanelOpen() {
+		return lipgloss.JoinHorizontal(
+			lipgloss.Top,
+			lipgloss.NewStyle().Width(WIDTH_LIST).AlignHorizontal(lipgloss.Left).Render(l),
+			STYLE_SPLIT.Height(m.h).Render(a.SidePanel(m.w-WIDTH_OFFSET_EDITOR, m.h)),
+		), nil
+	}
+
 	e, cur := m.editor.View()
 	if cur != nil {
 		cur.X += WIDTH_OFFSET_EDITOR
 	}
 
+	if a, ok := m.Abstraction.(interface {
+		EditorFooter(w, h int, e *editor.Model) string
+	}); ok {
+		w := m.w - WIDTH_OFFSET_EDITOR
+		if h := m.h - lipgloss.Height(e) - 2; h > 0 {
+			if f := a.EditorFooter(w, h, m.editor); f != "" {
+				e += "\n\n" + f
+			}
+		}
+	}
+
 	res := lipgloss.JoinHorizontal(
 		lipgloss.Top,
 		lipgloss.NewStyle().Width(WIDTH_LIST).AlignHorizontal(lipgloss.Left).Render(l),
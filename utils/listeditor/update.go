@@ -3,6 +3,7 @@ package listeditor
 import (
 	"slices"
 
+	"charm.land/bubbles/v2/key"
 	"charm.land/bubbles/v2/list"
 	tea "charm.land/bubbletea/v2"
 	"github.com/bank_data_tui/utils"
@@ -12,21 +13,62 @@ import (
 type ItemNew struct{ Value any }
 type ItemUpdate struct{ Value any }
 
+// Moves the list to the item with this id
+type SelectItem string
+
+// Selects the new item row, with the editor prefilled from Value (a PT without an id)
+type EditNew struct{ Value any }
+
+// Send when the abstraction changed items behind the list's back (ie. priorities). Re-sorts, & updates the
+// editor's fields that weren't typed into
+type ItemsChanged struct{}
+
+// Given to the abstraction's Update once the items are (re)fetched & sorted. Items is a []PT
+type ItemsLoaded struct{ Items any }
+
 func (m *Model[T, PT]) Update(msg tea.Msg) (utils.Screen, tea.Cmd) {
 	batcher := []tea.Cmd{}
 	var cmd tea.Cmd
 	bubble := true
 
 	switch msg := msg.(type) {
+	case SelectItem:
+		i := slices.IndexFunc(m.items, func(c PT) bool { return c.GetID() == string(msg) })
+		if i != -1 {
+			m.list.ResetFilter()
+			m.list.Select(i)
+		}
+	case EditNew:
+		m.list.ResetFilter()
+		m.list.Select(len(m.items))
+		m.curItem = msg.Value.(PT)
+		m.resetEditor()
+		batcher = append(batcher, m.editor.Init())
+	case ItemsChanged:
+		batcher = append(batcher, m.resort())
+		m.editor.Refresh()
 	case initialResp[PT]:
+		// On a refetch, items keep their pointers with the new values in them, since the editor (& abstraction) hold on to them
+		old := map[string]PT{}
+		for _, v := range m.items {
+			old[v.GetID()] = v
+		}
+		for i, v := range msg {
+			if o, ok := old[v.GetID()]; ok {
+				*o = *v
+				msg[i] = o
+			}
+		}
 		m.items = msg
-		cmd = m.list.SetItems(m.categoryItems())
-		batcher = append(batcher, cmd)
+		batcher = append(batcher, m.resort())
+		if a, ok := m.Abstraction.(interface{ Update(msg tea.Msg) }); ok {
+			a.Update(ItemsLoaded{Items: m.items})
+		}
 		m.isLoaded = true
 	case editor.ItemNew:
 		m.curItem.SetID(string(msg))
 		m.items = append(m.items, m.curItem)
-		batcher = append(batcher, m.list.SetItems(m.categoryItems()))
+		batcher = append(batcher, m.resort())
 		batcher = append(batcher, func() tea.Msg {
 			return ItemNew{Value: m.curItem}
 		})
@@ -37,15 +79,23 @@ func (m *Model[T, PT]) Update(msg tea.Msg) (utils.Screen, tea.Cmd) {
 		}
 		batcher = append(batcher, m.list.SetItems(m.categoryItems()))
 	case editor.ItemUpdate:
+		batcher = append(batcher, m.resort())
 		batcher = append(batcher, func() tea.Msg {
 			return ItemUpdate{Value: m.curItem}
 		})
 	case tea.KeyPressMsg:
-		switch msg.String() {
-		case "alt+up":
+		if a, ok := m.Abstraction.(KeyHandler[PT]); ok && m.isLoaded {
+			if cmd, handled := a.HandleKey(msg, m.curItem); handled {
+				m.editorUpdated()
+				return m, cmd
+			}
+		}
+
+		switch {
+		case key.Matches(msg, m.list.KeyMap.CursorUp):
 			bubble = false
 			m.list.CursorUp()
-		case "alt+down":
+		case key.Matches(msg, m.list.KeyMap.CursorDown):
 			bubble = false
 			m.list.CursorDown()
 		}
@@ -92,6 +142,7 @@ func (m *Model[T, PT]) Update(msg tea.Msg) (utils.Screen, tea.Cmd) {
 		m.resetEditor()
 		batcher = append(batcher, m.editor.Init())
 	}
+	m.editorUpdated()
 
 	return m, tea.Batch(batcher...)
 }
@@ -100,6 +151,23 @@ func (m *Model[T, PT]) isNewCategory(gi int) bool {
 	return gi >= len(m.items)
 }
 
+// Sorts the items if the abstraction wants that, keeping the current item selected
+func (m *Model[T, PT]) resort() tea.Cmd {
+	if a, ok := m.Abstraction.(Sorter[PT]); ok {
+		slices.SortStableFunc(m.items, a.Compare)
+	}
+
+	cmd := m.list.SetItems(m.categoryItems())
+	if m.curItem.GetID() != "" && m.list.FilterState() == list.Unfiltered {
+		i := slices.IndexFunc(m.items, func(c PT) bool { return c.GetID() == m.curItem.GetID() })
+		if i != -1 {
+			m.list.Select(i)
+		}
+	}
+
+	return cmd
+}
+
 func (m *Model[T, PT]) categoryItems() []list.Item {
 	arr := make([]list.Item, len(m.items)+1)
 	arr[len(arr)-1] = m.newItem
@@ -111,5 +179,5 @@ func (m *Model[T, PT]) categoryItems() []list.Item {
 }
 
 func (m *Model[item, PT]) resetEditor() {
-	m.editor = m.NewEditor(m.w, m.h, m.curItem)
+	m.editor = m.NewEditor(m.ctx, m.w, m.h, m.curItem)
 }
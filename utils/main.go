@@ -5,6 +5,7 @@ import (
 	"slices"
 	"strings"
 
+	"charm.land/bubbles/v2/key"
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
 	"github.com/charmbracelet/x/ansi"
@@ -22,7 +23,8 @@ func JoinHorizontalWithSpacer(w, spacerIndex int, str ...string) (string, []int)
 		widths += lipgloss.Width(s)
 	}
 
-	str = slices.Insert(str, spacerIndex, strings.Repeat(" ", w-widths))
+	// Overflows when it doesn't fit, rather than panicking
+	str = slices.Insert(str, spacerIndex, strings.Repeat(" ", max(w-widths, 0)))
 	off := 0
 	for i, v := range str {
 		if spacerIndex == i {
@@ -129,5 +131,25 @@ type Screen interface {
 	Init() tea.Cmd
 }
 
+// Screens implementing this are told when they're shown again after a tab switch, to reload anything that might be stale
+type Focuser interface {
+	Focus() tea.Cmd
+}
+
+// Screens implementing this get their keys listed in the help overlay. Only keys that work right now should be returned
+type KeyHelper interface {
+	KeyHelp() []key.Binding
+}
+
+// Screens implementing this get keys that type text (ie. ?) while Typing is true, instead of the help opening
+type Typer interface {
+	Typing() bool
+}
+
+// Sent when the api client can't go on without logging in again, see api.SessionExpiredErr
+type MsgSessionExpired struct {
+	Err error
+}
+
 type MsgGoToHome struct {}
 func GoToHome() tea.Msg { return MsgGoToHome{} }
\ No newline at end of file
@@ -0,0 +1,178 @@
+// Toasts for errors & other things that the user should know about, without killing the whole app
+package notify
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+	"github.com/bank_data_tui/api"
+	"github.com/bank_data_tui/utils"
+	"github.com/bank_data_tui/utils/keymap"
+)
+
+var (
+	KEY_RETRY   = key.NewBinding(key.WithKeys("ctrl+r"), key.WithHelp("ctrl+r", "retry"))
+	KEY_DISMISS = key.NewBinding(key.WithKeys("ctrl+x"), key.WithHelp("ctrl+x", "dismiss"))
+)
+
+func init() {
+	// Toasts take keys before any screen does
+	keymap.Register(keymap.GROUP_GLOBAL, map[string]*key.Binding{
+		"retry":   &KEY_RETRY,
+		"dismiss": &KEY_DISMISS,
+	})
+}
+
+type Severity int
+
+const (
+	SEV_INFO Severity = iota
+	SEV_WARN
+	SEV_ERR
+)
+
+// How long a toast stays on screen, per severity
+var LIFETIMES = map[Severity]time.Duration{
+	SEV_INFO: 3 * time.Second,
+	SEV_WARN: 5 * time.Second,
+	SEV_ERR:  8 * time.Second,
+}
+
+// Toasts with a retry action get some extra time for the user to react
+const RETRY_EXTRA_LIFETIME = 4 * time.Second
+
+// Only the newest MAX_VISIBLE toasts are kept
+const MAX_VISIBLE = 3
+
+// Send this from anywhere to show a toast
+type Msg struct {
+	Severity Severity
+	Text     string
+	// Re-issued when the user presses retry, nil if it can't be retried
+	Retry tea.Cmd
+}
+
+// Creates an error toast. Returns nil for cancelled contexts, since those are on purpose, & sends the app back to
+// the login for expired sessions
+func Error(err error, retry tea.Cmd) tea.Msg {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return nil
+	}
+	var expired *api.SessionExpiredErr
+	if errors.As(err, &expired) {
+		return utils.MsgSessionExpired{Err: err}
+	}
+
+	return Msg{
+		Severity: SEV_ERR,
+		Text:     err.Error(),
+		Retry:    retry,
+	}
+}
+
+func ErrorCmd(err error, retry tea.Cmd) tea.Cmd {
+	return func() tea.Msg {
+		return Error(err, retry)
+	}
+}
+
+func Info(text string) tea.Cmd {
+	return func() tea.Msg {
+		return Msg{Severity: SEV_INFO, Text: text}
+	}
+}
+
+func Warn(text string) tea.Cmd {
+	return func() tea.Msg {
+		return Msg{Severity: SEV_WARN, Text: text}
+	}
+}
+
+type toast struct {
+	Msg
+	id int
+}
+
+type dismiss int
+
+type Model struct {
+	toasts []*toast
+	nextID int
+}
+
+func (m *Model) Push(msg Msg) tea.Cmd {
+	t := &toast{Msg: msg, id: m.nextID}
+	m.nextID++
+
+	m.toasts = append(m.toasts, t)
+	if len(m.toasts) > MAX_VISIBLE {
+		m.toasts = m.toasts[len(m.toasts)-MAX_VISIBLE:]
+	}
+
+	life := LIFETIMES[msg.Severity]
+	if msg.Retry != nil {
+		life += RETRY_EXTRA_LIFETIME
+	}
+
+	return tea.Tick(life, func(time.Time) tea.Msg {
+		return dismiss(t.id)
+	})
+}
+
+func (m *Model) remove(id int) {
+	for i, t := range m.toasts {
+		if t.id == id {
+			m.toasts = append(m.toasts[:i], m.toasts[i+1:]...)
+			return
+		}
+	}
+}
+
+// Newest toast that can be retried, or nil
+func (m *Model) retryable() *toast {
+	for i := len(m.toasts) - 1; i >= 0; i-- {
+		if m.toasts[i].Retry != nil {
+			return m.toasts[i]
+		}
+	}
+
+	return nil
+}
+
+func (m Model) Empty() bool {
+	return len(m.toasts) == 0
+}
+
+// Reports if the msg was consumed by the notification system
+func (m *Model) Update(msg tea.Msg) (tea.Cmd, bool) {
+	switch msg := msg.(type) {
+	case Msg:
+		return m.Push(msg), true
+	case dismiss:
+		m.remove(int(msg))
+		return nil, true
+	case tea.KeyPressMsg:
+		switch {
+		case key.Matches(msg, KEY_RETRY):
+			t := m.retryable()
+			if t == nil {
+				return nil, false
+			}
+
+			m.remove(t.id)
+			return t.Retry, true
+		case key.Matches(msg, KEY_DISMISS):
+			if m.Empty() {
+				return nil, false
+			}
+
+			m.toasts = m.toasts[:len(m.toasts)-1]
+			return nil, true
+		}
+	}
+
+	return nil, false
+}
@@ -0,0 +1,73 @@
+package notify
+
+import (
+	"image/color"
+
+	"charm.land/lipgloss/v2"
+	"github.com/bank_data_tui/styles"
+)
+
+const (
+	MAX_WIDTH = 40
+	// Space between the toasts & the edge of the screen
+	MARGIN = 1
+)
+
+var TITLES = map[Severity]string{
+	SEV_INFO: "Info",
+	SEV_WARN: "Warning",
+	SEV_ERR:  "Error",
+}
+
+func severityColor(s Severity) color.Color {
+	switch s {
+	case SEV_WARN:
+		return styles.COLOR_SECONDARY
+	case SEV_ERR:
+		return styles.COLOR_WRONG
+	}
+
+	return styles.COLOR_MAIN
+}
+
+func (t *toast) render(w int) string {
+	c := severityColor(t.Severity)
+	lines := []string{
+		lipgloss.NewStyle().Bold(true).Foreground(c).Render(TITLES[t.Severity]),
+		t.Text,
+	}
+	if t.Retry != nil {
+		lines = append(lines, styles.S_TEXT_DISABLED.Render(KEY_RETRY.Help().Key+" retry · "+KEY_DISMISS.Help().Key+" dismiss"))
+	}
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(c).
+		Padding(0, 1).
+		Width(w).
+		Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
+
+// Draws the toasts on top of the bottom right of content, which is expected to be w x h
+func (m Model) Overlay(content string, w, h int) string {
+	if m.Empty() {
+		return content
+	}
+
+	tw := min(MAX_WIDTH, w/2)
+	rendered := make([]string, len(m.toasts))
+	for i, t := range m.toasts {
+		rendered[i] = t.render(tw)
+	}
+	stack := lipgloss.JoinVertical(lipgloss.Right, rendered...)
+
+	x := max(w-lipgloss.Width(stack)-MARGIN, 0)
+	y := max(h-lipgloss.Height(stack)-MARGIN, 0)
+
+	return lipgloss.NewCanvas(w, h).Compose(
+		lipgloss.NewCompositor(
+			lipgloss.NewLayer(content),
+			lipgloss.NewLayer(stack).X(x).Y(y).Z(1),
+		),
+	).Render()
+}
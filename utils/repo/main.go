@@ -1,17 +1,31 @@
 package repo
 
-import "github.com/bank_data_tui/api"
+import (
+	"context"
+
+	"github.com/bank_data_tui/api"
+)
 
 type Cache struct {
 	Categories []*api.Category
+	// nil when not loaded (or invalidated)
+	Mappings []*api.Mapping
+	// nil when not loaded (or invalidated)
+	Budgets []*api.Budget
+	// Most recent transactions, for client side matching. nil when not loaded (or invalidated), set by whoever
+	// fetched it once it's back on the UI goroutine
+	Sample []*api.Transaction
 }
 
-func (s *Cache) EasyCategories(c *api.APIClient) ([]*api.Category, error) {
+// Max pages of transactions loaded into Cache.Sample
+const SAMPLE_PAGES = 20
+
+func (s *Cache) EasyCategories(ctx context.Context, c *api.APIClient) ([]*api.Category, error) {
 	if s.Categories != nil {
 		return s.Categories, nil
 	}
 
-	v, err := c.CategoriesFetch()
+	v, err := c.CategoriesFetch(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -19,3 +33,60 @@ func (s *Cache) EasyCategories(c *api.APIClient) ([]*api.Category, error) {
 	s.Categories = v
 	return v, nil
 }
+
+func (s *Cache) EasyMappings(ctx context.Context, c *api.APIClient) ([]*api.Mapping, error) {
+	if s.Mappings != nil {
+		return s.Mappings, nil
+	}
+
+	v, err := c.MappingsFetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.Mappings = v
+	return v, nil
+}
+
+func (s *Cache) EasyBudgets(ctx context.Context, c *api.APIClient) ([]*api.Budget, error) {
+	if s.Budgets != nil {
+		return s.Budgets, nil
+	}
+
+	v, err := c.BudgetsFetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.Budgets = v
+	return v, nil
+}
+
+// Category with that id, or nil
+func (s *Cache) Category(id string) *api.Category {
+	for _, c := range s.Categories {
+		if c.ID == id {
+			return c
+		}
+	}
+
+	return nil
+}
+
+// Loads (up to SAMPLE_PAGES pages of) the most recent transactions, for Cache.Sample
+func FetchSample(ctx context.Context, c *api.APIClient) ([]*api.Transaction, error) {
+	all := []*api.Transaction{}
+	for p := 1; p <= SAMPLE_PAGES; p++ {
+		d, err := c.TransactionsFetch(ctx, api.TransactionQuery{Page: p})
+		if err != nil {
+			return nil, err
+		}
+
+		all = append(all, d.Data...)
+		if len(d.Data) == 0 || len(all) >= d.Total {
+			break
+		}
+	}
+
+	return all, nil
+}
@@ -0,0 +1,318 @@
+// Drives screens (& the widgets in them) with scripted messages & compares what they render to golden files.
+// Only meant for tests, run them with -update to rewrite the golden files after a change on purpose
+package screentest
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/bank_data_tui/api"
+	"github.com/bank_data_tui/api/fake"
+	"github.com/bank_data_tui/utils"
+	"github.com/charmbracelet/x/ansi"
+)
+
+var UPDATE = flag.Bool("update", false, "Rewrite the golden files instead of comparing against them")
+
+// Sizes (w, h) that views are usually checked at. The smallest is what the app allows before "Too Small"
+var SIZES = [][2]int{{50, 15}, {80, 19}, {120, 35}}
+
+// How long Settle waits for another message before deciding that the cmds left are timers
+var QUIET = 200 * time.Millisecond
+
+// Messages from these packages are cursor blinks & spinner frames, which would make every run look different
+var IGNORED_PKGS = []string{
+	"charm.land/bubbles/v2/cursor",
+	"charm.land/bubbles/v2/spinner",
+}
+
+// Where the golden files are, testdata in the package being tested. Absolute, so that tests can change dir
+var DIR string
+
+func init() {
+	// Dates are shown in local time
+	time.Local = time.UTC
+
+	var err error
+	if DIR, err = filepath.Abs("testdata"); err != nil {
+		panic(err)
+	}
+}
+
+// Anything with the Update & View of a screen. Update returns the updated model, like utils.Screen does
+type Model[T any] interface {
+	Update(msg tea.Msg) (T, tea.Cmd)
+	View() (string, *tea.Cursor)
+}
+
+type Harness[T Model[T]] struct {
+	t testing.TB
+	M T
+	// Every message that went into M, in order
+	Msgs []tea.Msg
+	// For models that wrap their children's messages, gives back what's inside so that it can be ignored
+	Unwrap func(tea.Msg) tea.Msg
+
+	pending []tea.Cmd
+}
+
+// Starts driving m. init is ran on the next Settle, pass m.Init() or nil
+func New[T Model[T]](t testing.TB, m T, init tea.Cmd) *Harness[T] {
+	return &Harness[T]{t: t, M: m, pending: []tea.Cmd{init}}
+}
+
+func (h *Harness[T]) ignored(msg tea.Msg) bool {
+	if h.Unwrap != nil {
+		msg = h.Unwrap(msg)
+	}
+
+	typ := reflect.TypeOf(msg)
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	return slices.Contains(IGNORED_PKGS, typ.PkgPath())
+}
+
+func (h *Harness[T]) update(msg tea.Msg) {
+	h.Msgs = append(h.Msgs, msg)
+
+	var cmd tea.Cmd
+	h.M, cmd = h.M.Update(msg)
+	h.pending = append(h.pending, cmd)
+}
+
+// Updates M with every msg in order. Cmds they return are only ran by Settle
+func (h *Harness[T]) Send(msgs ...tea.Msg) *Harness[T] {
+	h.t.Helper()
+	for _, msg := range msgs {
+		h.update(msg)
+	}
+
+	return h
+}
+
+// Presses keys in order, written like key.Binding keys (ie. "enter", "ctrl+n", "S")
+func (h *Harness[T]) Keys(keys ...string) *Harness[T] {
+	h.t.Helper()
+	for _, k := range keys {
+		h.update(Key(k))
+	}
+
+	return h
+}
+
+// Types text one key at a time
+func (h *Harness[T]) Type(text string) *Harness[T] {
+	h.t.Helper()
+	for _, r := range text {
+		h.update(tea.KeyPressMsg{Code: r, Text: string(r)})
+	}
+
+	return h
+}
+
+// Runs the pending cmds (& the ones they lead to) until nothing but timers is left. Their messages go into M as
+// they come back, except for IGNORED_PKGS
+func (h *Harness[T]) Settle() *Harness[T] {
+	h.t.Helper()
+
+	// Buffered, so that timers finishing after this returns don't block forever
+	res := make(chan tea.Msg, 1024)
+	running := 0
+	start := func(cmds ...tea.Cmd) {
+		for _, c := range cmds {
+			if c == nil {
+				continue
+			}
+			running++
+			go func() { res <- c() }()
+		}
+	}
+
+	start(h.pending...)
+	h.pending = nil
+
+	for running > 0 {
+		select {
+		case msg := <-res:
+			running--
+			switch msg := msg.(type) {
+			case nil:
+			case tea.BatchMsg:
+				start(msg...)
+			default:
+				if h.ignored(msg) {
+					continue
+				}
+				h.update(msg)
+				start(h.pending...)
+				h.pending = nil
+			}
+		case <-time.After(QUIET):
+			return h
+		}
+	}
+
+	return h
+}
+
+// What M renders, with the cursor's position on the last line if it has one
+func (h *Harness[T]) View() string {
+	v, c := h.M.View()
+	if c != nil {
+		v += fmt.Sprintf("\n--- cursor %d,%d", c.X, c.Y)
+	}
+
+	return v
+}
+
+// Compares the view, without colours & styles, to testdata/<name>.golden
+func (h *Harness[T]) Golden(name string) {
+	h.t.Helper()
+	Golden(h.t, name, ansi.Strip(h.View()))
+}
+
+// Compares the view, escape codes & all, to testdata/<name>.ansi.golden
+func (h *Harness[T]) GoldenANSI(name string) {
+	h.t.Helper()
+	Golden(h.t, name+".ansi", h.View())
+}
+
+// Compares got to testdata/<name>.golden, or writes it there with -update
+func Golden(t testing.TB, name, got string) {
+	t.Helper()
+
+	p := filepath.Join(DIR, name+".golden")
+	if *UPDATE {
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(p)
+	if err != nil {
+		t.Fatalf("%v (run with -update to create it)", err)
+	}
+	if string(want) != got {
+		t.Errorf("%s doesn't match (run with -update if that's on purpose)\n--- want\n%s\n--- got\n%s", p, want, got)
+	}
+}
+
+// Runs f as a subtest for every size, named like 80x19
+func Sizes(t *testing.T, sizes [][2]int, f func(t *testing.T, w, h int)) {
+	for _, s := range sizes {
+		t.Run(fmt.Sprintf("%dx%d", s[0], s[1]), func(t *testing.T) {
+			f(t, s[0], s[1])
+		})
+	}
+}
+
+var KEY_NAMES = map[string]rune{
+	"enter":     tea.KeyEnter,
+	"tab":       tea.KeyTab,
+	"backspace": tea.KeyBackspace,
+	"delete":    tea.KeyDelete,
+	"esc":       tea.KeyEscape,
+	"space":     tea.KeySpace,
+	"up":        tea.KeyUp,
+	"down":      tea.KeyDown,
+	"left":      tea.KeyLeft,
+	"right":     tea.KeyRight,
+	"home":      tea.KeyHome,
+	"end":       tea.KeyEnd,
+	"pgup":      tea.KeyPgUp,
+	"pgdown":    tea.KeyPgDown,
+	"f1":        tea.KeyF1,
+}
+
+var KEY_MODS = map[string]tea.KeyMod{
+	"ctrl":  tea.ModCtrl,
+	"alt":   tea.ModAlt,
+	"shift": tea.ModShift,
+}
+
+// A key press from the way key.Binding writes keys, ie. "alt+shift+up" or "?"
+func Key(k string) tea.KeyPressMsg {
+	// Split on the last +, unless that's the key itself
+	mods, name := "", k
+	if i := strings.LastIndex(k[:len(k)-1], "+"); i != -1 {
+		mods, name = k[:i], k[i+1:]
+	}
+
+	var msg tea.KeyPressMsg
+	if mods != "" {
+		for _, p := range strings.Split(mods, "+") {
+			mod, ok := KEY_MODS[p]
+			if !ok {
+				panic("screentest: unknown modifier in " + k)
+			}
+			msg.Mod |= mod
+		}
+	}
+
+	if code, ok := KEY_NAMES[name]; ok {
+		msg.Code = code
+		return msg
+	}
+
+	r := []rune(name)
+	if len(r) != 1 {
+		panic("screentest: unknown key " + k)
+	}
+	msg.Code = r[0]
+	if msg.Mod&^tea.ModShift == 0 {
+		msg.Text = name
+	}
+
+	return msg
+}
+
+// What the fake server's made up data is generated up to, so that it's the same every run
+var SEED_NOW = time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+
+// For a package's NOW, so that its periods line up with the seeded data
+func Now() time.Time {
+	return SEED_NOW
+}
+
+// A harness for the w x h screen that newScreen builds against a seeded Backend, settled after its Init
+func ScreenHarness(t testing.TB, w, h int, newScreen func(c *api.APIClient, w, h int) utils.Screen) *Harness[utils.Screen] {
+	t.Helper()
+
+	c, _ := Backend(t)
+	m := newScreen(c, w, h)
+
+	return New[utils.Screen](t, m, m.Init()).Settle()
+}
+
+// A fake server filled with Seed's data, and a client logged into it
+func Backend(t testing.TB) (*api.APIClient, *fake.Server) {
+	t.Helper()
+
+	s := fake.New()
+	s.Seed(SEED_NOW)
+	srv := httptest.NewServer(s)
+	t.Cleanup(srv.Close)
+
+	c := api.NewClient(api.WithBaseURL(srv.URL))
+	if err := c.Login(context.Background(), [2]string{"demo", "demo-password"}); err != nil {
+		t.Fatal(err)
+	}
+
+	return c, s
+}
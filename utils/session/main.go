@@ -0,0 +1,132 @@
+// A login kept between runs, in a file encrypted with a passphrase
+package session
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// Start of every session file, bumped if the format changes
+	MAGIC = "BDTS1"
+
+	SALT_LEN = 16
+	KEY_LEN  = 32
+	// OWASP's recommendation for PBKDF2-SHA256
+	KDF_ITERATIONS = 600_000
+)
+
+var (
+	ErrWrongPassphrase = errors.New("wrong passphrase, or the session file is damaged")
+	ErrBadFile         = errors.New("not a session file")
+)
+
+type Data struct {
+	// Tokens & passwords are only good for the server they came from
+	Server   string `json:"server"`
+	Token    string `json:"token,omitempty"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+func key(passphrase string, salt []byte) (cipher.AEAD, error) {
+	k, err := pbkdf2.Key(sha256.New, passphrase, salt, KDF_ITERATIONS, KEY_LEN)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(k)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// Encrypts d into p, which only the user can read
+func Save(p, passphrase string, d *Data) error {
+	plain, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+
+	salt := make([]byte, SALT_LEN)
+	rand.Read(salt)
+	aead, err := key(passphrase, salt)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	rand.Read(nonce)
+
+	out := append([]byte(MAGIC), salt...)
+	out = append(out, nonce...)
+	out = aead.Seal(out, nonce, plain, []byte(MAGIC))
+
+	if err := os.MkdirAll(filepath.Dir(p), 0o700); err != nil {
+		return err
+	}
+
+	// Written next to it first, so that a crash can't leave half a file behind
+	tmp := p + ".tmp"
+	if err := os.WriteFile(tmp, out, 0o600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, p)
+}
+
+// Decrypts the session in p. A missing file is (nil, nil)
+func Load(p, passphrase string) (*Data, error) {
+	raw, err := os.ReadFile(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.HasPrefix(raw, []byte(MAGIC)) || len(raw) < len(MAGIC)+SALT_LEN {
+		return nil, ErrBadFile
+	}
+	raw = raw[len(MAGIC):]
+
+	aead, err := key(passphrase, raw[:SALT_LEN])
+	if err != nil {
+		return nil, err
+	}
+	raw = raw[SALT_LEN:]
+	if len(raw) < aead.NonceSize() {
+		return nil, ErrBadFile
+	}
+
+	plain, err := aead.Open(nil, raw[:aead.NonceSize()], raw[aead.NonceSize():], []byte(MAGIC))
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+
+	d := &Data{}
+	if err := json.Unmarshal(plain, d); err != nil {
+		return nil, ErrBadFile
+	}
+
+	return d, nil
+}
+
+// Removes the session file, if there is one
+func Wipe(p string) error {
+	err := os.Remove(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	return err
+}